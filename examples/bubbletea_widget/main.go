@@ -10,14 +10,23 @@ import (
 	"github.com/erikgeiser/promptkit/selection"
 )
 
+type item struct {
+	name  string
+	stock int
+}
+
+func (i item) String() string {
+	return fmt.Sprintf("%s (%d left)", i.name, i.stock)
+}
+
 type shoppingCart struct {
-	availableItems []string
+	availableItems []item
 	addedItems     map[string]int
-	selection      *selection.Model[string]
+	selection      *selection.Model[item]
 	err            error
 }
 
-func newShoppingCart(items ...string) *shoppingCart {
+func newShoppingCart(items ...item) *shoppingCart {
 	return &shoppingCart{availableItems: items, addedItems: make(map[string]int)}
 }
 
@@ -40,14 +49,16 @@ func (s *shoppingCart) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch {
 	case keyMsg.String() == "enter":
-		c, err := s.selection.Value()
+		c, err := s.selection.ValueAsChoice()
 		if err != nil {
 			s.err = err
 
 			return s, tea.Quit
 		}
 
-		s.addedItems[c]++
+		s.addedItems[c.Value.name]++
+
+		return s, s.takeFromStock(c)
 	case keyMsg.String() == "esc":
 		return s, tea.Quit
 	default:
@@ -55,8 +66,26 @@ func (s *shoppingCart) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return s, cmd
 	}
+}
+
+// takeFromStock updates the choices of the selection such that the remaining
+// stock is displayed and items that are sold out can no longer be selected.
+func (s *shoppingCart) takeFromStock(c *selection.Choice[item]) tea.Cmd {
+	taken := item{name: c.Value.name, stock: c.Value.stock - 1}
+
+	if taken.stock > 0 {
+		_, cmd := s.selection.Update(selection.UpdateChoiceMsg[item]{Index: c.Index(), Value: taken})
+
+		return cmd
+	}
+
+	_, cmd := s.selection.Update(selection.RemoveChoicesMsg[item]{
+		Remove: func(choice *selection.Choice[item]) bool {
+			return choice.Value.name == taken.name
+		},
+	})
 
-	return s, nil
+	return cmd
 }
 
 func (s *shoppingCart) View() string {
@@ -83,7 +112,11 @@ func (s *shoppingCart) View() string {
 }
 
 func main() {
-	model := newShoppingCart("Apples", "Milk", "Bread")
+	model := newShoppingCart(
+		item{name: "Apples", stock: 5},
+		item{name: "Milk", stock: 2},
+		item{name: "Bread", stock: 1},
+	)
 
 	p := tea.NewProgram(model)
 
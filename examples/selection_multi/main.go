@@ -0,0 +1,27 @@
+// Package main demonstrates how promptkit/selection is used to select multiple
+// choices.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/erikgeiser/promptkit/selection"
+)
+
+func main() {
+	sp := selection.NewMulti("What do you pack?",
+		[]string{"Toothbrush", "Towel", "Passport", "Sunscreen", "Book"})
+	sp.PageSize = 4
+	sp.MinSelected = 1
+
+	choices, err := sp.RunPrompt()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+
+		os.Exit(1)
+	}
+
+	// do something with the final choices
+	_ = choices
+}
@@ -0,0 +1,49 @@
+// Package main demonstrates how promptkit/selection is used to browse files
+// with a preview of the highlighted file.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/erikgeiser/promptkit/selection"
+)
+
+func main() {
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+
+		os.Exit(1)
+	}
+
+	files := []string{}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, entry.Name())
+		}
+	}
+
+	sp := selection.New("Which file do you want to open?", files)
+	sp.PageSize = 10
+	sp.PreviewPosition = selection.PreviewRight
+	sp.Preview = func(c *selection.Choice[string]) string {
+		content, err := os.ReadFile(c.Value)
+		if err != nil {
+			return err.Error()
+		}
+
+		return string(content)
+	}
+
+	choice, err := sp.RunPrompt()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+
+		os.Exit(1)
+	}
+
+	// do something with the final choice
+	_ = choice
+}
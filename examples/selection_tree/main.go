@@ -0,0 +1,39 @@
+// Package main demonstrates how promptkit/selection is used to select a node of
+// a tree.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/erikgeiser/promptkit/selection"
+)
+
+func main() {
+	namespaces := []*selection.TreeNode[string]{
+		selection.NewTreeNode("default",
+			selection.NewTreeNode[string]("frontend"),
+			selection.NewTreeNode[string]("backend"),
+		),
+		selection.NewTreeNode("monitoring",
+			selection.NewTreeNode("prometheus",
+				selection.NewTreeNode[string]("server"),
+				selection.NewTreeNode[string]("alertmanager"),
+			),
+			selection.NewTreeNode[string]("grafana"),
+		),
+	}
+
+	sp := selection.NewTree("Which resource do you want to inspect?", namespaces)
+
+	path, err := sp.RunPrompt()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+
+		os.Exit(1)
+	}
+
+	// do something with the final path
+	_ = strings.Join(path, "/")
+}
@@ -2,6 +2,7 @@ package selection
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Choice represents a single choice. This type used as an input
@@ -10,13 +11,111 @@ type Choice[T any] struct {
 	idx    int
 	String string
 	Value  T
+
+	// Group is an optional label of the section in which the choice is listed.
+	// Consecutive choices of the same group are displayed below a common
+	// section header by the default template. Ungrouped choices that follow
+	// a group are separated from it by an empty line.
+	Group string
+
+	// Disabled choices are displayed but they cannot be selected and the
+	// cursor skips them. DisabledReason optionally explains why the choice is
+	// disabled.
+	Disabled       bool
+	DisabledReason string
+
+	// Hotkey is an optional key, such as "d" or "ctrl+d", that selects the
+	// choice regardless of the cursor position. The default templates display
+	// it after the choice. Printable hotkeys such as "d" are typed into the
+	// filter input while it is focused, so they only take effect if filtering
+	// is disabled or if the filter input is not focused in quick-select mode
+	// (see Selection.QuickSelect). Hotkeys must be unique and
+	// must neither conflict with the key map nor, if Selection.QuickSelect is
+	// enabled, with the quick-select labels; otherwise the prompt fails.
+	Hotkey string
+
+	matchPositions []int
+	// cells of the choice in table mode (see Selection.Columns)
+	tableCells []string
+	// whether the choice is the entry to create a new choice from the filter
+	// text (see Selection.CreateChoice)
+	create bool
 }
 
-// Index returns the current index of the choice.
+// Index returns the current index of the choice. The entry to create a new
+// choice from the filter text (see Selection.CreateChoice) and the choice
+// that is created from it have the index -1.
 func (c *Choice[T]) Index() int {
 	return c.idx
 }
 
+// MatchPositions returns the positions of the runes in the string
+// representation of the choice that were matched by the current filter text.
+// The positions are only available if Selection.MatchPositions is configured.
+func (c *Choice[T]) MatchPositions() []int {
+	return c.matchPositions
+}
+
+// HighlightMatches renders the string representation of the choice such that
+// the runes that were matched by the current filter text are rendered with
+// the highlight style and all other runes are rendered with the base style.
+// Both styles may be nil in which case the runes are rendered unstyled.
+func HighlightMatches[T any](c *Choice[T], base func(string) string,
+	highlight func(string) string,
+) string {
+	if base == nil {
+		base = func(s string) string { return s }
+	}
+
+	if highlight == nil {
+		highlight = func(s string) string { return s }
+	}
+
+	if len(c.matchPositions) == 0 {
+		return base(c.String)
+	}
+
+	matched := make(map[int]bool, len(c.matchPositions))
+	for _, pos := range c.matchPositions {
+		matched[pos] = true
+	}
+
+	var (
+		result  strings.Builder
+		segment []rune
+	)
+
+	runes := []rune(c.String)
+
+	for i, r := range runes {
+		segment = append(segment, r)
+
+		if i < len(runes)-1 && matched[i] == matched[i+1] {
+			continue
+		}
+
+		if matched[i] {
+			result.WriteString(highlight(string(segment)))
+		} else {
+			result.WriteString(base(string(segment)))
+		}
+
+		segment = segment[:0]
+	}
+
+	return result.String()
+}
+
+// NewChoice creates a new choice for the given value at the given index and
+// chooses a suitable string representation in the same way as for choices
+// passed to New. It is intended to be used by ChoiceSource implementations.
+func NewChoice[T any](index int, value T) *Choice[T] {
+	choice := newChoice(value)
+	choice.idx = index
+
+	return choice
+}
+
 // newChoice creates a new choice for a given input and chooses
 // a suitable string representation. The index is left at 0 to
 // be populated by the selection prompt later on.
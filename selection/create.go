@@ -0,0 +1,100 @@
+package selection
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateChoiceMode determines when the entry to create a new choice from the
+// filter text is offered (see Selection.CreateChoice).
+type CreateChoiceMode int
+
+const (
+	// CreateChoiceIfNoMatch offers the entry only if no choice matches the
+	// filter text.
+	CreateChoiceIfNoMatch CreateChoiceMode = iota
+	// CreateChoiceAlways offers the entry after the matching choices whenever
+	// a filter text is entered.
+	CreateChoiceAlways
+)
+
+// withCreateChoice appends the entry to create a new choice to the queried
+// choices if it is offered and falls within the queried range. The number of
+// available choices includes the entry.
+func (m *Model[T]) withCreateChoice(
+	choices []*Choice[T], available int, offset int, limit int,
+) ([]*Choice[T], int) {
+	if !m.isCreateChoiceOffered(available) {
+		return choices, available
+	}
+
+	// the entry is positioned after all matching choices
+	if offset <= available && available < offset+limit {
+		// copy the choices as they may be backed by the choice source
+		choices = append(choices[:len(choices):len(choices)], m.createEntry())
+	}
+
+	return choices, available + 1
+}
+
+// isCreateChoiceOffered returns true if the entry to create a new choice is
+// displayed for the current filter text given the number of matching choices.
+func (m *Model[T]) isCreateChoiceOffered(available int) bool {
+	if m.CreateChoice == nil || !m.canCreateChoices || m.filterText() == "" {
+		return false
+	}
+
+	return m.CreateChoiceMode == CreateChoiceAlways || available == 0
+}
+
+// createEntry returns the entry to create a new choice for the current filter
+// text. The same entry is reused while the filter text changes such that it
+// stays highlighted.
+func (m *Model[T]) createEntry() *Choice[T] {
+	if m.createChoiceEntry == nil {
+		m.createChoiceEntry = &Choice[T]{idx: -1, create: true}
+	}
+
+	m.createChoiceEntry.String = fmt.Sprintf("Create \"%s\"", m.filterText())
+
+	return m.createChoiceEntry
+}
+
+// createEntryPosition returns the position of the entry to create a new
+// choice, which follows all choices that match the filter text, if it is
+// offered.
+func (m *Model[T]) createEntryPosition() (int, bool) {
+	if m.filterText() == "" {
+		return 0, false
+	}
+
+	_, available := m.source.Query(m.filterText(), 0, 0)
+	if !m.isCreateChoiceOffered(available) {
+		return 0, false
+	}
+
+	return available, true
+}
+
+// createChoice replaces the value of the entry to create a new choice with
+// the value that is constructed from the filter text. If the value cannot be
+// constructed, the error is displayed and false is returned.
+func (m *Model[T]) createChoice(choice *Choice[T]) bool {
+	value, err := m.CreateChoice(m.filterText())
+	if err != nil {
+		m.createChoiceErr = err
+
+		return false
+	}
+
+	choice.Value = value
+	choice.String = newChoice(value).String
+
+	// in table mode, the choice is represented by its cells
+	if m.isTable() {
+		choice.tableCells = m.tableCells(value)
+		choice.String = strings.Join(choice.tableCells, " ")
+	}
+
+	return true
+}
@@ -0,0 +1,141 @@
+package selection_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func createBranch(name string) (string, error) {
+	if strings.Contains(name, " ") {
+		return "", fmt.Errorf("branch names cannot contain spaces")
+	}
+
+	return "feature/" + name, nil
+}
+
+func TestCreateChoice(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"main", "develop", "feature/login"})
+	s.CreateChoice = createBranch
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, test.MsgsFromText("signup")...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "create_choice.golden")
+
+	cmd := test.Update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("selecting the create entry did not quit the prompt")
+	}
+
+	if choice := getChoice(t, m); choice != "feature/signup" {
+		t.Errorf("unexpected choice: %q, expected feature/signup", choice)
+	}
+
+	test.AssertGoldenView(t, m, "create_choice_result.golden")
+}
+
+func TestCreateChoiceAlways(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"main", "develop", "feature/login"})
+	s.CreateChoice = createBranch
+	s.CreateChoiceMode = selection.CreateChoiceAlways
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append(test.MsgsFromText("log"), tea.KeyDown)...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "create_choice_always.golden")
+
+	test.Update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if choice := getChoice(t, m); choice != "feature/log" {
+		t.Errorf("unexpected choice: %q, expected feature/log", choice)
+	}
+}
+
+func TestCreateChoiceError(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"main", "develop", "feature/login"})
+	s.CreateChoice = createBranch
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append(test.MsgsFromText("sign up"), tea.KeyEnter)...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "create_choice_error.golden")
+
+	// the error disappears when the filter text is changed
+	test.Update(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+
+	if view := test.StripANSI(m.View()); strings.Contains(view, "cannot contain spaces") {
+		t.Errorf("error is still displayed after changing the filter text:\n%s", view)
+	}
+}
+
+func TestCreateChoiceStaysHighlighted(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"main", "develop", "feature/login"})
+	s.CreateChoice = createBranch
+	s.CreateChoiceMode = selection.CreateChoiceAlways
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append(append(test.MsgsFromText("log"), tea.KeyDown), test.KeyMsg('i'))...)
+	assertNoError(t, m)
+
+	choice, err := m.ValueAsChoice()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice.String != `Create "logi"` {
+		t.Errorf("unexpected highlighted choice: %q, expected the create entry", choice.String)
+	}
+}
+
+func TestCreateChoiceTable(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []pod{
+		{name: "api-7f9c6d-x2x9k", status: "Running", age: 3 * time.Hour},
+		{name: "db-0", status: "Running", age: 240 * time.Hour},
+	})
+	s.Columns = podColumns()
+	s.CreateChoice = func(name string) (pod, error) {
+		return pod{name: name, status: "Pending"}, nil
+	}
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append(test.MsgsFromText("web-0"), tea.KeyEnter)...)
+	assertNoError(t, m)
+
+	choice, err := m.ValueAsChoice()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice.String != "web-0 Pending 0s" {
+		t.Errorf("unexpected string representation of the created choice: %q", choice.String)
+	}
+
+	if choice.Index() != -1 {
+		t.Errorf("unexpected index of the created choice: %d, expected -1", choice.Index())
+	}
+}
@@ -0,0 +1,144 @@
+package selection
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	fuzzyScoreMatch        = 16
+	fuzzyBonusBoundary     = 10
+	fuzzyBonusFirstRune    = 8
+	fuzzyBonusConsecutive  = 8
+	fuzzyPenaltyGapStart   = 3
+	fuzzyPenaltyGapExtend  = 1
+	fuzzyPenaltyGapMaximum = 8
+)
+
+// FuzzyMatch matches the pattern against the text without regard for
+// capitalization. The pattern matches if all of its runes appear in the text in
+// the same order, though not necessarily consecutively. If the pattern matches,
+// a score is returned alongside the rune positions within the text that were
+// matched. Higher scores indicate better matches, especially matches at the
+// beginning of words and consecutive matches are preferred.
+func FuzzyMatch(pattern string, text string) (score int, positions []int, matched bool) {
+	patternRunes := []rune(strings.ToLower(pattern))
+	if len(patternRunes) == 0 {
+		return 0, nil, true
+	}
+
+	textRunes := []rune(text)
+
+	// find the earliest position at which the whole pattern is matched
+	end, patternIdx := -1, 0
+
+	for i, r := range textRunes {
+		if unicode.ToLower(r) != patternRunes[patternIdx] {
+			continue
+		}
+
+		patternIdx++
+		if patternIdx == len(patternRunes) {
+			end = i
+
+			break
+		}
+	}
+
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	// walk back from the end to find the shortest match that ends there
+	start, patternIdx := end, len(patternRunes)-1
+
+	for i := end; i >= 0; i-- {
+		if unicode.ToLower(textRunes[i]) != patternRunes[patternIdx] {
+			continue
+		}
+
+		patternIdx--
+		if patternIdx < 0 {
+			start = i
+
+			break
+		}
+	}
+
+	positions = make([]int, 0, len(patternRunes))
+	patternIdx = 0
+
+	for i := start; i <= end && patternIdx < len(patternRunes); i++ {
+		if unicode.ToLower(textRunes[i]) == patternRunes[patternIdx] {
+			positions = append(positions, i)
+			patternIdx++
+		}
+	}
+
+	return fuzzyScore(textRunes, positions), positions, true
+}
+
+func fuzzyScore(text []rune, positions []int) int {
+	score := 0
+
+	for i, pos := range positions {
+		score += fuzzyScoreMatch
+
+		if isWordBoundary(text, pos) {
+			score += fuzzyBonusBoundary
+		}
+
+		if pos == 0 {
+			score += fuzzyBonusFirstRune
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		gap := pos - positions[i-1] - 1
+		if gap == 0 {
+			score += fuzzyBonusConsecutive
+		} else {
+			score -= min(fuzzyPenaltyGapStart+fuzzyPenaltyGapExtend*(gap-1),
+				fuzzyPenaltyGapMaximum)
+		}
+	}
+
+	return score
+}
+
+// isWordBoundary returns true if the rune at the given position starts a new
+// word, e.g. it is the first rune, follows a non-alphanumeric rune or is an
+// upper case letter following a lower case letter.
+func isWordBoundary(text []rune, pos int) bool {
+	if pos == 0 {
+		return true
+	}
+
+	previous, current := text[pos-1], text[pos]
+
+	if !unicode.IsLetter(previous) && !unicode.IsDigit(previous) {
+		return true
+	}
+
+	return unicode.IsLower(previous) && unicode.IsUpper(current)
+}
+
+// FilterFuzzy returns true if the filter fuzzily matches the string
+// representation of the choice as described in FuzzyMatch.
+func FilterFuzzy[T any](filter string, choice *Choice[T]) bool {
+	_, _, matched := FuzzyMatch(filter, choice.String)
+
+	return matched
+}
+
+// FuzzyMatchPositions returns the positions of the runes in the string
+// representation of the choice that were fuzzily matched by the filter as
+// described in FuzzyMatch. It is intended to be used as
+// Selection.MatchPositions alongside FilterFuzzy.
+func FuzzyMatchPositions[T any](filter string, choice *Choice[T]) []int {
+	_, positions, _ := FuzzyMatch(filter, choice.String)
+
+	return positions
+}
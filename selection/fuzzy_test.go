@@ -0,0 +1,86 @@
+package selection_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pattern   string
+		text      string
+		matched   bool
+		positions []int
+	}{
+		{pattern: "", text: "anything", matched: true},
+		{pattern: "abc", text: "abc", matched: true, positions: []int{0, 1, 2}},
+		{pattern: "ABC", text: "xaxbxc", matched: true, positions: []int{1, 3, 5}},
+		{pattern: "svc", text: "my-service", matched: true, positions: []int{3, 6, 8}},
+		{pattern: "ba", text: "abc", matched: false},
+		{pattern: "öx", text: "fÖöx", matched: true, positions: []int{2, 3}},
+	}
+
+	for _, testCase := range testCases {
+		_, positions, matched := selection.FuzzyMatch(testCase.pattern, testCase.text)
+		if matched != testCase.matched {
+			t.Errorf("matching %q against %q resulted in %v, expected %v",
+				testCase.pattern, testCase.text, matched, testCase.matched)
+		}
+
+		if !reflect.DeepEqual(positions, testCase.positions) {
+			t.Errorf("matching %q against %q resulted in positions %v, expected %v",
+				testCase.pattern, testCase.text, positions, testCase.positions)
+		}
+	}
+}
+
+func TestFuzzyMatchScore(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pattern string
+		better  string
+		worse   string
+	}{
+		{pattern: "api", better: "api-gateway", worse: "rapid-deploy"},
+		{pattern: "gw", better: "git-worktree", worse: "engwrite"},
+		{pattern: "auth", better: "auth-service", worse: "a-u-t-h"},
+		{pattern: "us", better: "UserService", worse: "bonus"},
+	}
+
+	for _, testCase := range testCases {
+		betterScore, _, _ := selection.FuzzyMatch(testCase.pattern, testCase.better)
+		worseScore, _, _ := selection.FuzzyMatch(testCase.pattern, testCase.worse)
+
+		if betterScore <= worseScore {
+			t.Errorf("matching %q: %q scored %d which is not better than %d for %q",
+				testCase.pattern, testCase.better, betterScore, worseScore, testCase.worse)
+		}
+	}
+}
+
+func TestFuzzyFilterHighlight(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewModel(selection.New("foo:", []string{
+		"api-gateway", "billing", "auth-proxy", "audit-log",
+	}))
+	m.Filter = selection.FilterFuzzy[string]
+	m.MatchPositions = selection.FuzzyMatchPositions[string]
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, test.MsgsFromText("ag")...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "fuzzy_highlight.golden")
+
+	choice := getChoice(t, m)
+	if choice != "api-gateway" {
+		t.Errorf("unexpected choice: %v, expected api-gateway", choice)
+	}
+}
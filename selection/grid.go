@@ -0,0 +1,109 @@
+package selection
+
+import (
+	"github.com/muesli/reflow/ansi"
+)
+
+const (
+	// DefaultGridTemplate defines the default appearance of the selection in
+	// grid mode and can be copied as a starting point for a custom template.
+	// It is used instead of the DefaultTemplate if Grid is enabled.
+	DefaultGridTemplate = `
+{{- template "header" . }}
+
+{{- range  $i, $choice := .Choices }}
+  {{- if IsGroupStart $i }}
+    {{- template "groupHeader" $choice }}
+    {{- if not (IsRowStart $i) }}{{ "  " }}{{ end }}
+  {{- end }}
+  {{- if IsRowStart $i }}{{ template "scrollHint" $i }}{{ end }}
+
+  {{- $cell := "" }}
+  {{- if $.IsQuickSelect }}
+    {{- with QuickSelectLabel $i }}
+      {{- $cell = print (Faint .) " " }}
+    {{- else }}
+      {{- $cell = "  " }}
+    {{- end }}
+  {{- end }}
+  {{- if $choice.Disabled }}
+    {{- $cell = print $cell "  " (Disabled $choice) }}
+  {{- else if eq $.SelectedIndex $i }}
+    {{- $cell = print $cell (Foreground "32" (Bold "▸ ")) (Selected $choice) }}
+  {{- else }}
+    {{- $cell = print $cell "  " (Unselected $choice) }}
+  {{- end }}
+  {{- with $choice.Hotkey }}
+    {{- $cell = print $cell " " (Faint (print "[" . "]")) }}
+  {{- end }}
+
+  {{- if or (IsRowEnd $i) (IsGroupStart (Add $i 1)) }}
+    {{- print $cell "\n" }}
+  {{- else }}
+    {{- print $cell (Repeat " " (Max 0 (Sub $.GridCellWidth (Len $cell)))) }}
+  {{- end }}
+{{- end}}
+{{- template "footer" . }}`
+
+	// defaultGridWidth is the width of the grid if the terminal width is
+	// unknown.
+	defaultGridWidth = 80
+
+	// gridCellPadding is the space in each cell of the grid that is not
+	// occupied by the string representation of the choice, which includes the
+	// cursor and the gap to the next column.
+	gridCellPadding = 4
+
+	// gridHintWidth is the width of the scroll hints in front of each row.
+	gridHintWidth = 2
+)
+
+// rowLength returns the number of choices per row, which is the number of
+// columns in grid mode and 1 otherwise.
+func (m *Model[T]) rowLength() int {
+	if !m.Grid {
+		return 1
+	}
+
+	width := m.width
+	if width <= 0 {
+		width = defaultGridWidth
+	}
+
+	return max(1, (width-gridHintWidth)/max(1, m.gridCellWidth))
+}
+
+// updateGridCellWidth widens the cells of the grid such that the given choices
+// fit into them.
+func (m *Model[T]) updateGridCellWidth(choices []*Choice[T]) {
+	padding := gridCellPadding
+	if m.QuickSelect {
+		padding += quickSelectLabelWidth
+	}
+
+	for _, choice := range choices {
+		m.gridCellWidth = max(m.gridCellWidth, ansi.PrintableRuneWidth(choice.String)+hotkeyWidth(choice)+padding)
+	}
+}
+
+func (m *Model[T]) isRowStart(idx int) bool {
+	return idx%m.rowLength() == 0
+}
+
+func (m *Model[T]) isRowEnd(idx int) bool {
+	return (idx+1)%m.rowLength() == 0 || idx == len(m.currentChoices)-1
+}
+
+func (m *Model[T]) cursorLeft() {
+	position, ok := m.nextSelectablePosition(m.currentPosition(), -1)
+	if ok {
+		m.moveToPosition(position)
+	}
+}
+
+func (m *Model[T]) cursorRight() {
+	position, ok := m.nextSelectablePosition(m.currentPosition(), 1)
+	if ok {
+		m.moveToPosition(position)
+	}
+}
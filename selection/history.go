@@ -0,0 +1,326 @@
+package selection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyDirName is the directory within the XDG state directory in which the
+// histories are stored.
+const historyDirName = "promptkit"
+
+const (
+	// historyMaxEntries is the number of choices that are kept in a history.
+	// The choices that were picked least recently are dropped first.
+	historyMaxEntries = 1000
+	// historyLockTimeout is the duration for which recording a pick waits for
+	// other prompts that are writing the same history.
+	historyLockTimeout = 2 * time.Second
+	// historyLockRetryInterval is the pause between attempts to acquire the
+	// lock of a history file.
+	historyLockRetryInterval = 10 * time.Millisecond
+	// historyStaleLockAge is the age after which a lock file is considered to
+	// be left behind by a prompt that did not release it.
+	historyStaleLockAge = 10 * time.Second
+)
+
+// recency weights by which the number of times a choice was picked is
+// multiplied depending on when it was picked most recently.
+const (
+	hourWeight  = 8
+	dayWeight   = 4
+	weekWeight  = 2
+	olderWeight = 1
+)
+
+// History records which choices were picked in a selection prompt such that
+// they can be ordered by frecency, a combination of how frequently and how
+// recently they were picked, the next time the prompt is displayed. Choices
+// are identified by their string representation. A History is persisted to a
+// file each time a pick is recorded and only the 1000 most recently picked
+// choices are kept.
+type History struct {
+	path    string
+	entries map[string]historyEntry
+	now     func() time.Time
+}
+
+type historyEntry struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// NewHistory loads the history of the prompt with the given ID from the user's
+// XDG state directory, which is $XDG_STATE_HOME or ~/.local/state if it is not
+// set. An empty history is returned if nothing was recorded for the prompt ID
+// yet.
+func NewHistory(promptID string) (*History, error) {
+	if promptID == "" || strings.ContainsAny(promptID, `/\`) || promptID == "." || promptID == ".." {
+		return nil, fmt.Errorf("invalid prompt ID %q", promptID)
+	}
+
+	stateDir, err := xdgStateDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHistoryFromFile(filepath.Join(stateDir, historyDirName, promptID+".json"))
+}
+
+// NewHistoryFromFile loads the history that is stored in the given file. An
+// empty history is returned if the file does not exist yet.
+func NewHistoryFromFile(path string) (*History, error) {
+	entries, err := readHistory(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &History{path: path, entries: entries, now: time.Now}, nil
+}
+
+func readHistory(path string) (map[string]historyEntry, error) {
+	entries := make(map[string]historyEntry)
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return entries, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	err = json.Unmarshal(content, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("parse history %s: %w", path, err)
+	}
+
+	return entries, nil
+}
+
+func xdgStateDir() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir != "" {
+		return stateDir, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("determine state directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".local", "state"), nil
+}
+
+// Record records that the choices with the given string representations were
+// picked and persists the history. The picks are merged with the history file
+// as other prompts may have recorded picks since it was loaded.
+func (h *History) Record(choices ...string) error {
+	err := os.MkdirAll(filepath.Dir(h.path), 0o700) //nolint:gomnd
+	if err != nil {
+		return fmt.Errorf("create history directory: %w", err)
+	}
+
+	unlock, err := lockHistory(h.path)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	entries, err := readHistory(h.path)
+	if err != nil {
+		return err
+	}
+
+	now := h.now()
+
+	for _, choice := range choices {
+		entry := entries[choice]
+		entry.Count++
+		entry.LastUsed = now
+		entries[choice] = entry
+	}
+
+	pruneHistory(entries)
+
+	err = writeHistory(h.path, entries)
+	if err != nil {
+		return err
+	}
+
+	h.entries = entries
+
+	return nil
+}
+
+// Score returns the frecency score of the choice with the given string
+// representation. Choices that were never picked have a score of 0.
+func (h *History) Score(choice string) int {
+	entry, ok := h.entries[choice]
+	if !ok {
+		return 0
+	}
+
+	age := h.now().Sub(entry.LastUsed)
+
+	switch {
+	case age < time.Hour:
+		return entry.Count * hourWeight
+	case age < 24*time.Hour:
+		return entry.Count * dayWeight
+	case age < 7*24*time.Hour:
+		return entry.Count * weekWeight
+	default:
+		return entry.Count * olderWeight
+	}
+}
+
+// pruneHistory drops the entries that were used least recently such that at
+// most historyMaxEntries entries remain.
+func pruneHistory(entries map[string]historyEntry) {
+	if len(entries) <= historyMaxEntries {
+		return
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return entries[keys[i]].LastUsed.After(entries[keys[j]].LastUsed)
+	})
+
+	for _, key := range keys[historyMaxEntries:] {
+		delete(entries, key)
+	}
+}
+
+// lockHistory creates a lock file next to the history file such that
+// concurrently running prompts do not overwrite each other's picks. It returns
+// a function that releases the lock. Lock files that are older than
+// historyStaleLockAge are removed.
+func lockHistory(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(historyLockTimeout)
+
+	for {
+		lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600) //nolint:gomnd
+		if err == nil {
+			_ = lockFile.Close()
+
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("lock history: %w", err)
+		}
+
+		info, err := os.Stat(lockPath)
+		if err == nil && time.Since(info.ModTime()) > historyStaleLockAge {
+			_ = os.Remove(lockPath)
+
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("lock history: %s is locked by another prompt", path)
+		}
+
+		time.Sleep(historyLockRetryInterval)
+	}
+}
+
+// writeHistory writes the history to a temporary file which then replaces the
+// history file such that concurrently running prompts never read a partial
+// history.
+func writeHistory(path string, entries map[string]historyEntry) error {
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("marshal history: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create history file: %w", err)
+	}
+
+	_, err = tmpFile.Write(content)
+	closeErr := tmpFile.Close()
+
+	if err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+
+		return fmt.Errorf("write history: %w", err)
+	}
+
+	return nil
+}
+
+// sortByFrecency orders the choices by their frecency score in descending
+// order. Choices with equal scores keep their order.
+func (m *Model[T]) sortByFrecency(choices []*Choice[T]) {
+	scores := make(map[*Choice[T]]int, len(choices))
+	for _, choice := range choices {
+		scores[choice] = m.History.Score(m.historyKey(choice))
+	}
+
+	sort.SliceStable(choices, func(i, j int) bool {
+		return scores[choices[i]] > scores[choices[j]]
+	})
+}
+
+// historyKey returns the string by which the choice is identified in the
+// history. In table mode, these are the cells, which may not have been
+// determined yet.
+func (m *Model[T]) historyKey(choice *Choice[T]) string {
+	if m.isTable() && choice.tableCells == nil {
+		return strings.Join(m.tableCells(choice.Value), " ")
+	}
+
+	return choice.String
+}
+
+// historyRecordedMsg signals that the picks were recorded in the history such
+// that the prompt can conclude.
+type historyRecordedMsg struct{}
+
+// recordHistory returns a command that records the given choices in the
+// history if one is configured and concludes the prompt afterwards. The
+// history is written in the background as it may have to wait for other
+// prompts that write the same history. Errors are ignored as the choices were
+// picked regardless of whether they could be recorded.
+func (m *Model[T]) recordHistory(choices ...*Choice[T]) tea.Cmd {
+	if m.History == nil || len(choices) == 0 {
+		return tea.Quit
+	}
+
+	picked := make([]string, 0, len(choices))
+	for _, choice := range choices {
+		picked = append(picked, m.historyKey(choice))
+	}
+
+	history := m.History
+
+	return func() tea.Msg {
+		_ = history.Record(picked...)
+
+		return historyRecordedMsg{}
+	}
+}
@@ -0,0 +1,365 @@
+package selection
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit/test"
+)
+
+func TestHistoryScore(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	h, err := NewHistoryFromFile(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	h.now = func() time.Time { return now.Add(-30 * 24 * time.Hour) }
+
+	for i := 0; i < 3; i++ {
+		err = h.Record("billing")
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	h.now = func() time.Time { return now.Add(-10 * time.Minute) }
+
+	err = h.Record("checkout")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	h.now = func() time.Time { return now }
+
+	if score := h.Score("billing"); score != 3 {
+		t.Errorf("unexpected score of old picks: %d, expected 3", score)
+	}
+
+	if score := h.Score("checkout"); score != 8 {
+		t.Errorf("unexpected score of recent pick: %d, expected 8", score)
+	}
+
+	if score := h.Score("search"); score != 0 {
+		t.Errorf("unexpected score of unpicked choice: %d, expected 0", score)
+	}
+}
+
+func TestHistorySelection(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "deploy.json")
+	services := []string{"billing", "checkout", "search", "users"}
+
+	h, err := NewHistoryFromFile(path)
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	s := New("foo:", services)
+	s.History = h
+
+	m := NewModel(s)
+
+	test.Run(t, m, tea.KeyDown, tea.KeyDown)
+	submitWithHistory(t, m)
+
+	if m.Err != nil {
+		t.Fatalf("model contains error: %v", m.Err)
+	}
+
+	// the pick is persisted and moves the choice to the top the next time
+	h, err = NewHistoryFromFile(path)
+	if err != nil {
+		t.Fatalf("reload history: %v", err)
+	}
+
+	s = New("foo:", services)
+	s.History = h
+
+	m = NewModel(s)
+
+	test.Run(t, m)
+
+	choice, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice != "search" {
+		t.Errorf("unexpected first choice: %q, expected search", choice)
+	}
+
+	if idx := m.currentChoices[1].Index(); idx != 1 {
+		t.Errorf("unexpected index of second choice: %d, expected 1", idx)
+	}
+}
+
+func TestHistoryUnwritable(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	h, err := NewHistoryFromFile(filepath.Join(dir, "state", "deploy.json"))
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	// the history directory cannot be created where a file exists
+	err = os.WriteFile(filepath.Join(dir, "state"), nil, 0o600)
+	if err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	s := New("foo:", []string{"billing", "checkout"})
+	s.History = h
+
+	m := NewModel(s)
+
+	test.Run(t, m, tea.KeyDown)
+	submitWithHistory(t, m)
+
+	choice, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice != "checkout" {
+		t.Errorf("unexpected choice: %q, expected checkout", choice)
+	}
+}
+
+// submitWithHistory submits the highlighted choice and records it in the
+// history.
+func submitWithHistory[T any](tb testing.TB, m *Model[T]) {
+	tb.Helper()
+
+	cmd := test.Update(tb, m, tea.KeyEnter)
+	if cmd == nil {
+		tb.Fatalf("submitting did not record the history")
+	}
+
+	cmd = test.Update(tb, m, cmd())
+	if cmd == nil {
+		tb.Fatalf("prompt did not conclude after recording the history")
+	}
+
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		tb.Fatalf("prompt did not conclude after recording the history")
+	}
+}
+
+func TestHistoryInvalidPromptID(t *testing.T) {
+	t.Parallel()
+
+	for _, promptID := range []string{"", "..", "deploy/prod"} {
+		_, err := NewHistory(promptID)
+		if err == nil {
+			t.Errorf("expected error for prompt ID %q", promptID)
+		}
+	}
+}
+
+func TestHistoryStateDir(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+
+	h, err := NewHistory("deploy")
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	expectedPath := filepath.Join(stateDir, "promptkit", "deploy.json")
+	if h.path != expectedPath {
+		t.Errorf("unexpected history path %q, expected %q", h.path, expectedPath)
+	}
+}
+
+func TestHistoryConcurrentPrompts(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	first, err := NewHistoryFromFile(path)
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	second, err := NewHistoryFromFile(path)
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	for _, record := range []func() error{
+		func() error { return first.Record("billing") },
+		func() error { return second.Record("checkout") },
+		func() error { return first.Record("checkout") },
+	} {
+		err = record()
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	h, err := NewHistoryFromFile(path)
+	if err != nil {
+		t.Fatalf("reload history: %v", err)
+	}
+
+	if h.entries["billing"].Count != 1 || h.entries["checkout"].Count != 2 {
+		t.Errorf("picks of concurrent prompts were lost: %v", h.entries)
+	}
+}
+
+func TestHistoryPruned(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	h, err := NewHistoryFromFile(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	h.now = func() time.Time { return now.Add(-time.Hour) }
+
+	err = h.Record("oldest")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	choices := make([]string, 0, historyMaxEntries)
+	for i := 0; i < historyMaxEntries; i++ {
+		choices = append(choices, fmt.Sprintf("choice-%d", i))
+	}
+
+	h.now = func() time.Time { return now }
+
+	err = h.Record(choices...)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	if len(h.entries) != historyMaxEntries {
+		t.Errorf("unexpected number of entries: %d, expected %d", len(h.entries), historyMaxEntries)
+	}
+
+	if _, ok := h.entries["oldest"]; ok {
+		t.Errorf("least recently picked choice was not dropped")
+	}
+}
+
+func TestHistoryStaleLock(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	err := os.WriteFile(path+".lock", nil, 0o600)
+	if err != nil {
+		t.Fatalf("write lock file: %v", err)
+	}
+
+	stale := time.Now().Add(-2 * historyStaleLockAge)
+
+	err = os.Chtimes(path+".lock", stale, stale)
+	if err != nil {
+		t.Fatalf("change lock file time: %v", err)
+	}
+
+	h, err := NewHistoryFromFile(path)
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	err = h.Record("billing")
+	if err != nil {
+		t.Fatalf("record with stale lock: %v", err)
+	}
+}
+
+func TestHistoryInitialIndex(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "history.json")
+	services := []string{"billing", "checkout", "search", "users"}
+
+	h, err := NewHistoryFromFile(path)
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	err = h.Record("users")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	s := New("foo:", services)
+	s.History = h
+	s.InitialIndex = 1
+
+	m := NewModel(s)
+
+	test.Run(t, m)
+
+	choice, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice != "checkout" {
+		t.Errorf("unexpected initial choice: %q, expected checkout", choice)
+	}
+}
+
+func TestHistorySortColumn(t *testing.T) {
+	t.Parallel()
+
+	h, err := NewHistoryFromFile(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatalf("new history: %v", err)
+	}
+
+	err = h.Record("c 1", "b 2")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	type service struct {
+		name     string
+		priority int
+	}
+
+	s := New("foo:", []service{{"a", 1}, {"b", 2}, {"c", 1}})
+	s.History = h
+	s.Columns = []Column[service]{
+		{Header: "NAME", Value: func(s service) string { return s.name }},
+		{Header: "PRIORITY", Value: func(s service) string { return fmt.Sprint(s.priority) }},
+	}
+	s.SortColumn = "PRIORITY"
+
+	m := NewModel(s)
+
+	test.Run(t, m)
+
+	if m.Err != nil {
+		t.Fatalf("model contains error: %v", m.Err)
+	}
+
+	order := ""
+	for _, choice := range m.currentChoices {
+		order += choice.Value.name
+	}
+
+	// the choices are sorted by priority and the history orders the choices
+	// with equal priorities
+	if order != "cab" {
+		t.Errorf("unexpected order %q, expected cab", order)
+	}
+}
@@ -0,0 +1,127 @@
+package selection
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/ansi"
+)
+
+// hotkeyDecorationWidth is the width of the brackets and the space with which
+// the hotkey is displayed after the choice in the default templates.
+const hotkeyDecorationWidth = 3
+
+// hotkeyChoice returns the choice whose hotkey was pressed or nil if the key is
+// not the hotkey of a selectable choice. While the filter input is focused,
+// printable keys are typed into it and are not considered to be hotkeys.
+func (m *Model[T]) hotkeyChoice(msg tea.KeyMsg) *Choice[T] {
+	printable := msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace
+	if printable && m.isFilterEnabled() && m.filterInput.Focused() {
+		return nil
+	}
+
+	choice := m.hotkeyChoices[msg.String()]
+
+	// the choices of custom choice sources are not indexed, such that only
+	// the displayed choices can be selected with their hotkeys
+	if _, ok := m.source.(*sliceSource[T]); !ok {
+		choice = nil
+
+		for _, displayed := range m.currentChoices {
+			if displayed.Hotkey != "" && displayed.Hotkey == msg.String() {
+				choice = displayed
+
+				break
+			}
+		}
+	}
+
+	if choice == nil || choice.Disabled {
+		return nil
+	}
+
+	return choice
+}
+
+// indexHotkeys replaces the index of the hotkeys with that of the given
+// choices.
+func (m *Model[T]) indexHotkeys(choices []*Choice[T]) {
+	m.hotkeyChoices = make(map[string]*Choice[T], len(choices))
+
+	for _, choice := range choices {
+		m.indexHotkey(choice)
+	}
+}
+
+// indexHotkey adds the hotkey of the choice to the index of the hotkeys unless
+// it is already assigned to a preceding choice.
+func (m *Model[T]) indexHotkey(choice *Choice[T]) {
+	if choice.Hotkey != "" && m.hotkeyChoices[choice.Hotkey] == nil {
+		m.hotkeyChoices[choice.Hotkey] = choice
+	}
+}
+
+// highlightChoice moves the cursor to the given choice. If the choice is hidden
+// by the filter, the filter is cleared. It returns false if the choice is not
+// displayed.
+func (m *Model[T]) highlightChoice(choice *Choice[T]) bool {
+	position, ok := m.choicePosition(choice)
+	if !ok && m.filterText() != "" {
+		m.filterInput.Reset()
+		m.createChoiceErr = nil
+		m.compileFilterRegexp()
+
+		position, ok = m.choicePosition(choice)
+	}
+
+	if ok {
+		m.moveToPosition(position)
+	}
+
+	// the choices of custom choice sources can only be found among the
+	// displayed choices
+	for idx, displayed := range m.currentChoices {
+		if displayed == choice {
+			m.currentIdx = idx
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// hotkeys returns the hotkeys of all choices that are known before the prompt
+// is run, which excludes the choices of custom choice sources.
+func (s *Selection[T]) hotkeys() []string {
+	var choices []*Choice[T]
+
+	switch src := s.source.(type) {
+	case *sliceSource[T]:
+		choices = src.choices
+	case *treeSource[T]:
+		src.reindex()
+
+		for _, node := range src.nodes {
+			choices = append(choices, node.Choice)
+		}
+	}
+
+	var hotkeys []string
+
+	for _, choice := range choices {
+		if choice.Hotkey != "" {
+			hotkeys = append(hotkeys, choice.Hotkey)
+		}
+	}
+
+	return hotkeys
+}
+
+// hotkeyWidth returns the width that the hotkey of the choice takes up in the
+// default templates.
+func hotkeyWidth[T any](choice *Choice[T]) int {
+	if choice.Hotkey == "" {
+		return 0
+	}
+
+	return ansi.PrintableRuneWidth(choice.Hotkey) + hotkeyDecorationWidth
+}
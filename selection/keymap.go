@@ -2,6 +2,7 @@ package selection
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -10,13 +11,22 @@ import (
 // also be used as a starting point for customization.
 func NewDefaultKeyMap() *KeyMap {
 	return &KeyMap{
-		Down:        []string{"down"},
-		Up:          []string{"up"},
-		Select:      []string{"enter"},
-		Abort:       []string{"ctrl+c"},
-		ClearFilter: []string{"esc"},
-		ScrollDown:  []string{"pgdown"},
-		ScrollUp:    []string{"pgup"},
+		Down:              []string{"down"},
+		Up:                []string{"up"},
+		Select:            []string{"enter"},
+		Abort:             []string{"ctrl+c"},
+		ClearFilter:       []string{"esc"},
+		ScrollDown:        []string{"pgdown"},
+		ScrollUp:          []string{"pgup"},
+		FocusFilter:       []string{"/"},
+		ToggleRegexFilter: []string{"ctrl+t"},
+		Left:              []string{"left"},
+		Right:             []string{"right"},
+		Toggle:            []string{"tab"},
+		SelectAll:         []string{"ctrl+a"},
+		Invert:            []string{"ctrl+r"},
+		Expand:            []string{"right"},
+		Collapse:          []string{"left"},
 	}
 }
 
@@ -29,6 +39,26 @@ type KeyMap struct {
 	ClearFilter []string
 	ScrollDown  []string
 	ScrollUp    []string
+
+	// FocusFilter is only used if Selection.QuickSelect is enabled.
+	FocusFilter []string
+
+	// ToggleRegexFilter switches the filter input between the configured
+	// filter and regular expressions (see Selection.RegexFilter).
+	ToggleRegexFilter []string
+
+	// Left and Right are only used if Selection.Grid is enabled.
+	Left  []string
+	Right []string
+
+	// The following keys are only used by the multi-selection prompt.
+	Toggle    []string
+	SelectAll []string
+	Invert    []string
+
+	// The following keys are only used by the tree selection prompt.
+	Expand   []string
+	Collapse []string
 }
 
 func keyMatches(key tea.KeyMsg, mapping []string) bool {
@@ -41,10 +71,19 @@ func keyMatches(key tea.KeyMsg, mapping []string) bool {
 	return false
 }
 
-// validateKeyMap returns true if the given key map contains at
-// least the bare minimum set of key bindings for the functional
-// prompt and false otherwise.
-func validateKeyMap(km *KeyMap) error {
+// keyBinding is a named set of keys of a KeyMap.
+type keyBinding struct {
+	name string
+	keys []string
+}
+
+// validateKeyMap returns an error if the key map of the selection lacks the
+// bare minimum set of key bindings for the functional prompt. Additionally, it
+// ensures that the given hotkeys of the choices are unique and that they
+// conflict neither with the key map nor with the quick-select labels.
+func validateKeyMap[T any](s *Selection[T], hotkeys ...string) error {
+	km := s.KeyMap
+
 	if len(km.Up) == 0 {
 		return fmt.Errorf("no up key")
 	}
@@ -61,5 +100,99 @@ func validateKeyMap(km *KeyMap) error {
 		return fmt.Errorf("no abort key")
 	}
 
+	seen := make(map[string]bool, len(hotkeys))
+
+	for _, hotkey := range hotkeys {
+		if seen[hotkey] {
+			return fmt.Errorf("hotkey %q is assigned to multiple choices", hotkey)
+		}
+
+		seen[hotkey] = true
+
+		if s.QuickSelect && len(hotkey) == 1 && strings.Contains(quickSelectLabels, hotkey) {
+			return fmt.Errorf("hotkey %q conflicts with a quick-select label", hotkey)
+		}
+	}
+
+	bindings := []keyBinding{
+		{name: "down", keys: km.Down},
+		{name: "up", keys: km.Up},
+		{name: "select", keys: km.Select},
+		{name: "abort", keys: km.Abort},
+		{name: "clear filter", keys: km.ClearFilter},
+		{name: "scroll down", keys: km.ScrollDown},
+		{name: "scroll up", keys: km.ScrollUp},
+		{name: "focus filter", keys: km.FocusFilter},
+		{name: "toggle regex filter", keys: km.ToggleRegexFilter},
+	}
+
+	// the left and right keys are only bound in grid mode
+	if s.Grid {
+		bindings = append(bindings,
+			keyBinding{name: "left", keys: km.Left},
+			keyBinding{name: "right", keys: km.Right},
+		)
+	}
+
+	return validateHotkeys(hotkeys, bindings...)
+}
+
+// validateHotkeys ensures that none of the hotkeys is used by the bindings.
+func validateHotkeys(hotkeys []string, bindings ...keyBinding) error {
+	for _, hotkey := range hotkeys {
+		for _, binding := range bindings {
+			for _, key := range binding.keys {
+				if key == hotkey {
+					return fmt.Errorf("hotkey %q conflicts with the %s key", hotkey, binding.name)
+				}
+			}
+		}
+	}
+
 	return nil
 }
+
+// validateMultiKeyMap works like validateKeyMap but additionally ensures that
+// the key map can be used for a multi-selection prompt.
+func validateMultiKeyMap[T any](s *Selection[T], hotkeys ...string) error {
+	err := validateKeyMap(s, hotkeys...)
+	if err != nil {
+		return err
+	}
+
+	km := s.KeyMap
+
+	if len(km.Toggle) == 0 {
+		return fmt.Errorf("no toggle key")
+	}
+
+	return validateHotkeys(hotkeys,
+		keyBinding{name: "toggle", keys: km.Toggle},
+		keyBinding{name: "select all", keys: km.SelectAll},
+		keyBinding{name: "invert", keys: km.Invert},
+	)
+}
+
+// validateTreeKeyMap works like validateKeyMap but additionally ensures that
+// the key map can be used for a tree selection prompt.
+func validateTreeKeyMap[T any](s *Selection[T], hotkeys ...string) error {
+	err := validateKeyMap(s, hotkeys...)
+	if err != nil {
+		return err
+	}
+
+	km := s.KeyMap
+
+	if len(km.Expand) == 0 {
+		return fmt.Errorf("no expand key")
+	}
+
+	if len(km.Collapse) == 0 {
+		return fmt.Errorf("no collapse key")
+	}
+
+	return validateHotkeys(hotkeys,
+		keyBinding{name: "expand", keys: km.Expand},
+		keyBinding{name: "collapse", keys: km.Collapse},
+	)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"regexp"
 	"text/template"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -38,6 +39,69 @@ type Model[T any] struct {
 	tmpl              *template.Template
 	resultTmpl        *template.Template
 	requestedPageSize int
+	// whether choices are still being received from the choice stream and
+	// whether the InitialChoice may still be among them
+	loading               bool
+	awaitingInitialChoice bool
+	// preview of the highlighted choice, the index of the choice for which
+	// the preview was requested most recently and the sequence number of that
+	// request
+	preview          string
+	previewIdx       int
+	previewSeq       int
+	previewRequested bool
+	previewAvailable bool
+	// keystrokes of the type-ahead search that is used when filtering is
+	// disabled and a counter to detect pauses between them
+	typeAheadText string
+	typeAheadSeq  int
+	// whether the left mouse button is currently pressed, whether the
+	// displayed choices are marked in the view to locate clicked choices and
+	// the choices that are passed to the template while it is rendered
+	mousePressed    bool
+	markChoices     bool
+	renderedChoices []*Choice[T]
+	// width of the cells in grid mode
+	gridCellWidth int
+	// header row in table mode, the widths of the contents of the columns,
+	// the widths of the columns after fitting them to the terminal width, the
+	// width of the hotkeys after the rows and the width in front of each row
+	// in the default template of the prompt
+	tableHeader        string
+	tableContentWidths []int
+	tableColumnWidths  []int
+	tableHotkeyWidth   int
+	rowPrefixWidth     int
+	// whether the prompt variant supports creating choices from the filter
+	// text and the error of the last attempt
+	canCreateChoices  bool
+	createChoiceErr   error
+	createChoiceEntry *Choice[T]
+	// error of the last attempt to change the choices of a custom choice
+	// source, which is displayed until the next key press
+	choicesErr error
+	// choices of the choice slice by their hotkeys
+	hotkeyChoices map[string]*Choice[T]
+	// last valid regular expression of the filter input in regex mode and
+	// the error of the current filter text if it is not valid
+	filterRegexp    *regexp.Regexp
+	filterRegexpErr error
+
+	// extraTemplateFuncs and extraTemplateData allow prompt variants that
+	// build upon this model, such as MultiModel, to extend the template.
+	extraTemplateFuncs template.FuncMap
+	extraTemplateData  func() map[string]interface{}
+
+	// finalTemplateData provides the result of the prompt variant to the
+	// ResultTemplate, such as FinalChoice.
+	finalTemplateData func() (map[string]interface{}, error)
+
+	// choicesChanged is called after the choices were changed by a message
+	// such as AddChoicesMsg, which may change the indices of the choices.
+	choicesChanged func()
+
+	// validateKeyMap validates the key map for the respective prompt variant.
+	validateKeyMap func() error
 
 	quitting bool
 }
@@ -48,19 +112,91 @@ var _ tea.Model = &Model[any]{}
 // NewModel returns a new selection prompt model for the
 // provided choices.
 func NewModel[T any](selection *Selection[T]) *Model[T] {
-	return &Model[T]{Selection: selection}
+	m := &Model[T]{
+		Selection:        selection,
+		rowPrefixWidth:   defaultRowPrefixWidth,
+		canCreateChoices: true,
+	}
+
+	m.validateKeyMap = func() error {
+		return validateKeyMap(m.Selection, m.hotkeys()...)
+	}
+	m.finalTemplateData = func() (map[string]interface{}, error) {
+		choice, err := m.ValueAsChoice()
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"FinalChoice": choice}, nil
+	}
+
+	return m
 }
 
 // Init initializes the selection prompt model.
 func (m *Model[T]) Init() tea.Cmd {
-	m.reindexChoices()
+	err := m.validateKeyMap()
+	if err != nil {
+		m.Err = fmt.Errorf("insufficient key map: %w", err)
+
+		return tea.Quit
+	}
+
+	src, isSliceSource := m.source.(*sliceSource[T])
+
+	// a non-zero InitialIndex refers to the original order of the choices
+	var initialChoice *Choice[T]
+	if isSliceSource && m.InitialIndex > 0 && m.InitialIndex < src.Len() {
+		initialChoice = src.choices[m.InitialIndex]
+	}
+
+	// the SortColumn takes precedence over the history, which only orders
+	// the choices with equal values in the column
+	if isSliceSource && m.History != nil {
+		m.sortByFrecency(src.choices)
+	}
+
+	if m.isTable() {
+		m.Err = m.initTable()
+		if m.Err != nil {
+			return tea.Quit
+		}
+	}
 
-	if len(m.choices) == 0 {
+	if isSliceSource {
+		m.updateTable()
+
+		for i, choice := range src.choices {
+			if choice == initialChoice {
+				m.InitialIndex = i
+			}
+		}
+
+		src.reindex()
+		src.match = m.matchesFilter
+		src.score = nil
+
+		if m.ScoreFilter != nil {
+			src.score = m.scoreFilter
+		}
+
+		src.invalidateFilterCache()
+	}
+
+	m.indexHotkeys(m.allChoices())
+
+	if m.source.Len() == 0 && m.ChoiceStream == nil {
 		m.Err = fmt.Errorf("no choices provided")
 
 		return tea.Quit
 	}
 
+	if m.ChoiceStream != nil && !isSliceSource {
+		m.Err = fmt.Errorf("choice streams are not supported with custom choice sources")
+
+		return tea.Quit
+	}
+
 	if m.Template == "" {
 		m.Err = fmt.Errorf("empty template")
 
@@ -78,13 +214,26 @@ func (m *Model[T]) Init() tea.Cmd {
 	}
 
 	m.filterInput = m.initFilterInput()
+	m.compileFilterRegexp()
 
-	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
-
+	// in grid mode, the page size is the number of rows
 	m.requestedPageSize = m.PageSize
 
+	if m.Grid {
+		// the choices of custom choice sources may be created lazily, so only
+		// the first ones determine the cell width
+		gridChoices := m.allChoices()
+		if gridChoices == nil {
+			gridChoices = m.source.Range(0, min(m.source.Len(), initialChoiceBatchSize))
+		}
+
+		m.updateGridCellWidth(gridChoices)
+		m.PageSize = m.requestedPageSize * m.rowLength()
+	}
+
 	// try to get an initial terminal size in order to avoid initial overdrawing
-	// which can cause ugly glitches on some terminals
+	// which can cause ugly glitches on some terminals and such that only the
+	// choices that fit the terminal are queried
 	outputFile, ok := m.Output.(*os.File)
 	if ok {
 		width, height, err := term.GetSize(int(outputFile.Fd()))
@@ -93,7 +242,23 @@ func (m *Model[T]) Init() tea.Cmd {
 		}
 	}
 
-	return textinput.Blink
+	// custom choice sources may provide a huge number of choices which must
+	// not be queried all at once while the terminal size is unknown
+	if m.PageSize <= 0 && m.isCustomSource() {
+		m.PageSize = defaultSourcePageSize * m.rowLength()
+	}
+
+	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+	m.moveToPosition(m.initialPosition())
+	m.ensureSelectable()
+
+	if m.ChoiceStream != nil {
+		m.loading = true
+
+		return tea.Batch(textinput.Blink, m.receiveChoices(), m.updatePreview())
+	}
+
+	return tea.Batch(textinput.Blink, m.updatePreview())
 }
 
 func (m *Model[T]) initTemplate() (*template.Template, error) {
@@ -103,26 +268,50 @@ func (m *Model[T]) initTemplate() (*template.Template, error) {
 	tmpl.Funcs(promptkit.UtilFuncMap())
 	tmpl.Funcs(template.FuncMap{
 		"IsScrollDownHintPosition": func(idx int) bool {
-			return m.canScrollDown() && (idx == len(m.currentChoices)-1)
+			lastRowStart := (len(m.currentChoices) - 1) / m.rowLength() * m.rowLength()
+
+			return m.canScrollDown() && (idx == lastRowStart)
 		},
 		"IsScrollUpHintPosition": func(idx int) bool {
 			return m.canScrollUp() && idx == 0 && m.scrollOffset > 0
 		},
+		"IsGroupStart":     m.isGroupStart,
+		"IsCreateChoice":   func(c *Choice[T]) bool { return c.create },
+		"QuickSelectLabel": m.quickSelectLabel,
+		"IsRowStart":       m.isRowStart,
+		"IsRowEnd":         m.isRowEnd,
+		"Disabled": func(c *Choice[T]) string {
+			if m.DisabledChoiceStyle == nil {
+				return m.markChoice(c, c.String)
+			}
+
+			return m.markChoice(c, m.DisabledChoiceStyle(c))
+		},
 		"Selected": func(c *Choice[T]) string {
 			if m.SelectedChoiceStyle == nil {
-				return c.String
+				return m.markChoice(c, c.String)
 			}
 
-			return m.SelectedChoiceStyle(c)
+			return m.markChoice(c, m.SelectedChoiceStyle(c))
 		},
 		"Unselected": func(c *Choice[T]) string {
 			if m.UnselectedChoiceStyle == nil {
-				return c.String
+				return m.markChoice(c, c.String)
 			}
 
-			return m.UnselectedChoiceStyle(c)
+			return m.markChoice(c, m.UnselectedChoiceStyle(c))
 		},
 	})
+	tmpl.Funcs(m.extraTemplateFuncs)
+
+	_, err := tmpl.Parse(templateBlocks)
+	if err != nil {
+		return nil, fmt.Errorf("parse template blocks: %w", err)
+	}
+
+	if m.Grid && m.Template == DefaultTemplate {
+		return tmpl.Parse(DefaultGridTemplate)
+	}
 
 	return tmpl.Parse(m.Template)
 }
@@ -157,7 +346,16 @@ func (m *Model[T]) initFilterInput() textinput.Model {
 	filterInput.Cursor.Style = m.FilterInputCursorStyle
 	filterInput.Placeholder = m.FilterPlaceholder
 	filterInput.Width = 80
-	filterInput.Focus()
+
+	if m.QuickSelect && m.FilterPlaceholder == DefaultFilterPlaceholder {
+		filterInput.Placeholder = m.quickSelectFilterPlaceholder()
+	}
+
+	// in quick-select mode, typed keys select choices until the filter input
+	// is focused explicitly
+	if !m.QuickSelect {
+		filterInput.Focus()
+	}
 
 	return filterInput
 }
@@ -194,12 +392,37 @@ func (m *Model[T]) Value() (T, error) {
 
 // Update updates the model based on the received message.
 func (m *Model[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+
+	previewCmd := m.updatePreview()
+	if previewCmd == nil {
+		return model, cmd
+	}
+
+	return model, tea.Batch(cmd, previewCmd)
+}
+
+func (m *Model[T]) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.Err != nil {
 		return m, tea.Quit
 	}
 
+	// the choice was submitted and the prompt concludes once it was recorded
+	// in the history
+	if m.quitting {
+		if _, ok := msg.(historyRecordedMsg); ok {
+			return m, tea.Quit
+		}
+
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.userInteracted()
+
+		hotkeyChoice := m.hotkeyChoice(msg)
+
 		switch {
 		case keyMatches(msg, m.KeyMap.Abort):
 			m.Err = promptkit.ErrAborted
@@ -207,33 +430,93 @@ func (m *Model[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			return m, tea.Quit
 		case keyMatches(msg, m.KeyMap.Select):
-			if len(m.currentChoices) == 0 {
+			choice, err := m.ValueAsChoice()
+			if err != nil || choice.Disabled {
 				return m, nil
 			}
 
-			m.quitting = true
-
-			return m, tea.Quit
+			return m, m.submit()
+		case m.QuickSelect && m.filterInput.Focused() && keyMatches(msg, m.KeyMap.ClearFilter):
+			m.filterInput.Blur()
 		case keyMatches(msg, m.KeyMap.ClearFilter):
+			highlighted := m.highlightedChoice()
+
 			m.filterInput.Reset()
-			m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+			m.createChoiceErr = nil
+			m.compileFilterRegexp()
+			m.applyFilter(highlighted)
 		case keyMatches(msg, m.KeyMap.Down):
 			m.cursorDown()
 		case keyMatches(msg, m.KeyMap.Up):
 			m.cursorUp()
+		case m.Grid && keyMatches(msg, m.KeyMap.Left):
+			m.cursorLeft()
+		case m.Grid && keyMatches(msg, m.KeyMap.Right):
+			m.cursorRight()
 		case keyMatches(msg, m.KeyMap.ScrollDown):
 			m.scrollDown()
+			m.ensureSelectable()
 		case keyMatches(msg, m.KeyMap.ScrollUp):
 			m.scrollUp()
+			m.ensureSelectable()
+		case m.isRegexFilterSupported() && keyMatches(msg, m.KeyMap.ToggleRegexFilter):
+			m.toggleRegexFilter()
+		case m.isQuickSelectActive() && m.isFilterEnabled() && keyMatches(msg, m.KeyMap.FocusFilter):
+			return m, m.filterInput.Focus()
+		case hotkeyChoice != nil:
+			if !m.highlightChoice(hotkeyChoice) {
+				return m, nil
+			}
+
+			return m, m.submit()
+		case m.quickSelectIndex(msg) >= 0:
+			choice := m.currentChoices[m.quickSelectIndex(msg)]
+			if choice.Disabled {
+				return m, nil
+			}
+
+			m.currentIdx = m.quickSelectIndex(msg)
+
+			return m, m.submit()
 		default:
+			if !m.isFilterEnabled() {
+				return m, m.typeAhead(msg)
+			}
+
 			return m.updateFilter(msg)
 		}
 
 		return m, nil
+	case tea.MouseMsg:
+		m.userInteracted()
+
+		return m, m.updateMouse(msg)
 	case tea.WindowSizeMsg:
 		m.resize(msg.Width, msg.Height)
 
 		return m, tea.ClearScrollArea
+	case choiceStreamMsg[T]:
+		return m, m.addStreamedChoices(msg)
+	case AddChoicesMsg[T]:
+		return m, m.addChoices(msg)
+	case RemoveChoicesMsg[T]:
+		return m, m.removeChoices(msg)
+	case ReplaceChoicesMsg[T]:
+		return m, m.replaceChoices(msg)
+	case UpdateChoiceMsg[T]:
+		return m, m.updateChoice(msg)
+	case previewDebounceMsg:
+		return m, m.computePreview(msg)
+	case previewMsg:
+		m.receivePreview(msg)
+
+		return m, nil
+	case typeAheadResetMsg:
+		if msg.seq == m.typeAheadSeq {
+			m.typeAheadText = ""
+		}
+
+		return m, nil
 	case error:
 		m.Err = msg
 
@@ -246,63 +529,145 @@ func (m *Model[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model[T]) resize(width int, height int) {
+	previousWidth := m.width
 	m.width = zeroAwareMin(width, m.MaxWidth)
 
-	if m.height != height {
+	// in table mode, the columns are truncated according to the width
+	if m.width != previousWidth {
+		m.layoutTable()
+	}
+
+	// in grid mode, the number of choices per page depends on the width
+	gridChanged := m.Grid && m.width != previousWidth
+
+	switch {
+	case m.height != height || (gridChanged && height > 0):
 		m.height = height
 		m.forceUpdatePageSizeForHeight()
+	case gridChanged:
+		m.PageSize = m.requestedPageSize * m.rowLength()
+		m.moveToPosition(m.currentPosition())
+		m.ensureSelectable()
 	}
 }
 
 func (m *Model[T]) forceUpdatePageSizeForHeight() {
-	maxAcceptablePageSize := len(m.choices)
+	maxAcceptablePageSize := max(m.source.Len(), m.availableChoices)
 	if m.requestedPageSize != 0 {
-		maxAcceptablePageSize = min(len(m.choices), m.requestedPageSize)
+		maxAcceptablePageSize = min(maxAcceptablePageSize, m.requestedPageSize*m.rowLength())
 	}
 
-	// try preferred page size first
-	m.PageSize = maxAcceptablePageSize
+	// remember the position such that the highlighted choice stays highlighted
+	// and visible after the page size changed
+	position, scrollOffset := m.currentPosition(), m.scrollOffset
+
 	m.currentIdx = 0
 	m.scrollOffset = 0
-	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 
-	if lipgloss.Height(m.View()) < m.height {
-		return
+	if maxAcceptablePageSize > 0 {
+		m.PageSize = m.fittingPageSize(maxAcceptablePageSize)
+	} else {
+		m.PageSize = maxAcceptablePageSize
 	}
 
-	// if it does not fit, brute force a fitting page size
-	for m.PageSize = 1; m.PageSize <= maxAcceptablePageSize; m.PageSize++ {
-		m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+	m.scrollOffset = scrollOffset
+	m.moveToPosition(position)
+	m.ensureSelectable()
+}
 
-		if lipgloss.Height(m.View()) >= m.height {
-			m.PageSize--
-			m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+// fittingPageSize returns the largest page size up to maxPageSize with which
+// the view fits the terminal height. Instead of rendering the view for each
+// candidate page size, the height of the fixed parts of the view and the height
+// per choice are measured once to calculate the page size directly. As choices
+// may span a different number of lines, the result is verified and corrected
+// if necessary.
+func (m *Model[T]) fittingPageSize(maxPageSize int) int {
+	// in grid mode, whole rows of choices are measured
+	rowLength := m.rowLength()
+
+	singleRowViewHeight := m.viewHeightForPageSize(rowLength)
+
+	rowHeight := 1
+	if maxPageSize > rowLength && m.availableChoices > rowLength {
+		rowHeight = m.viewHeightForPageSize(2*rowLength) - singleRowViewHeight
+	}
 
-			return
-		}
+	pageSize := maxPageSize
+
+	if rowHeight > 0 {
+		fixedHeight := singleRowViewHeight - rowHeight
+		pageSize = max(rowLength, min(maxPageSize, (m.height-1-fixedHeight)/rowHeight*rowLength))
 	}
 
-	m.PageSize--
+	for pageSize > rowLength && m.viewHeightForPageSize(pageSize) >= m.height {
+		pageSize -= rowLength
+	}
+
+	return pageSize
+}
+
+func (m *Model[T]) viewHeightForPageSize(pageSize int) int {
+	m.PageSize = pageSize
 	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+
+	return lipgloss.Height(m.View())
 }
 
 func (m *Model[T]) updateFilter(msg tea.Msg) (*Model[T], tea.Cmd) {
-	if m.Filter == nil {
+	if !m.isFilterEnabled() {
 		return m, nil
 	}
 
 	previousFilter := m.filterInput.Value()
+	highlighted := m.highlightedChoice()
 
 	var cmd tea.Cmd
 	m.filterInput, cmd = m.filterInput.Update(msg)
 
 	if m.filterInput.Value() != previousFilter {
+		m.createChoiceErr = nil
+		m.compileFilterRegexp()
+		m.applyFilter(highlighted)
+	}
+
+	return m, cmd
+}
+
+// applyFilter updates the displayed choices after the filter changed. If the
+// previously highlighted choice still matches the filter, it stays highlighted
+// in the same row if possible, otherwise the first choice is highlighted.
+func (m *Model[T]) applyFilter(highlighted *Choice[T]) {
+	position, found := m.choicePosition(highlighted)
+	if !found {
 		m.currentIdx = 0
 		m.scrollOffset = 0
 		m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+		m.ensureSelectable()
+
+		return
 	}
 
-	return m, cmd
+	rowLength := m.rowLength()
+	m.scrollOffset = max(0, position/rowLength-m.currentIdx/rowLength) * rowLength
+	m.moveToPosition(position)
+	m.ensureSelectable()
+}
+
+// submit concludes the prompt with the highlighted choice and records it in
+// the history.
+func (m *Model[T]) submit() tea.Cmd {
+	choice, err := m.ValueAsChoice()
+	if err == nil && choice.create && !m.createChoice(choice) {
+		return nil
+	}
+
+	m.quitting = true
+
+	if err != nil {
+		return tea.Quit
+	}
+
+	return m.recordHistory(choice)
 }
 
 // View renders the selection prompt.
@@ -325,26 +690,48 @@ func (m *Model[T]) View() string {
 		return ""
 	}
 
-	err := m.tmpl.Execute(viewBuffer, map[string]interface{}{
+	m.renderedChoices = m.displayedChoices()
+
+	data := map[string]interface{}{
 		"Prompt":        m.Prompt,
-		"IsFiltered":    m.Filter != nil,
+		"IsFiltered":    m.isFilterEnabled(),
+		"IsRegexFilter": m.isRegexFilterActive(),
 		"FilterPrompt":  m.FilterPrompt,
 		"FilterInput":   m.filterInput.View(),
-		"Choices":       m.currentChoices,
+		"FilterError":   m.filterError(),
+		"ChoicesError":  m.choicesError(),
+		"Choices":       m.renderedChoices,
 		"NChoices":      len(m.currentChoices),
 		"SelectedIndex": m.currentIdx,
 		"PageSize":      m.PageSize,
 		"IsPaged":       m.PageSize > 0 && len(m.currentChoices) > m.PageSize,
-		"AllChoices":    m.choices,
-		"NAllChoices":   len(m.choices),
+		"AllChoices":    m.allChoices(),
+		"NAllChoices":   m.source.Len(),
 		"TerminalWidth": m.width,
-	})
+		"Loading":       m.loading,
+		"IsQuickSelect": m.isQuickSelectActive(),
+		"GridColumns":   m.rowLength(),
+		"GridCellWidth": m.gridCellWidth,
+		"TableHeader":   m.tableHeader,
+	}
+
+	if m.extraTemplateData != nil {
+		for key, value := range m.extraTemplateData() {
+			data[key] = value
+		}
+	}
+
+	err := m.tmpl.Execute(viewBuffer, data)
 	if err != nil {
 		m.Err = err
 
 		return "Template Error: " + err.Error()
 	}
 
+	if m.Preview != nil {
+		return m.withPreview(viewBuffer.String())
+	}
+
 	return m.wrap(viewBuffer.String())
 }
 
@@ -359,18 +746,23 @@ func (m *Model[T]) resultView() (string, error) {
 		return "", fmt.Errorf("rendering confirmation without loaded template")
 	}
 
-	choice, err := m.ValueAsChoice()
+	data := map[string]interface{}{
+		"Prompt":        m.Prompt,
+		"AllChoices":    m.allChoices(),
+		"NAllChoices":   m.source.Len(),
+		"TerminalWidth": m.width,
+	}
+
+	finalData, err := m.finalTemplateData()
 	if err != nil {
 		return "", err
 	}
 
-	err = m.resultTmpl.Execute(viewBuffer, map[string]interface{}{
-		"FinalChoice":   choice,
-		"Prompt":        m.Prompt,
-		"AllChoices":    m.choices,
-		"NAllChoices":   len(m.choices),
-		"TerminalWidth": m.width,
-	})
+	for key, value := range finalData {
+		data[key] = value
+	}
+
+	err = m.resultTmpl.Execute(viewBuffer, data)
 	if err != nil {
 		return "", fmt.Errorf("execute confirmation template: %w", err)
 	}
@@ -379,35 +771,288 @@ func (m *Model[T]) resultView() (string, error) {
 }
 
 func (m *Model[T]) wrap(text string) string {
+	return m.wrapWidth(text, m.width)
+}
+
+func (m *Model[T]) wrapWidth(text string, width int) string {
 	if m.WrapMode == nil {
 		return text
 	}
 
-	return m.WrapMode(text, m.width)
+	return m.WrapMode(text, width)
 }
 
 func (m *Model[T]) filteredAndPagedChoices() ([]*Choice[T], int) {
-	choices := []*Choice[T]{}
+	var (
+		choices   []*Choice[T]
+		available int
+	)
+
+	if m.PageSize <= 0 {
+		choices, available = m.queryAllChoices()
+	} else {
+		choices, available = m.queryChoices(m.scrollOffset, m.PageSize)
+	}
 
-	var available, ignored int
+	m.updateMatchPositions(choices)
 
-	for _, choice := range m.choices {
-		if m.Filter != nil && !m.Filter(m.filterInput.Value(), choice) {
-			continue
+	return choices, available
+}
+
+// queryAllChoices returns all choices that match the current filter. Sources
+// such as the one of a tree selection may report more matches than choices
+// that are displayed without a filter, in which case they are queried again.
+func (m *Model[T]) queryAllChoices() ([]*Choice[T], int) {
+	choices, available := m.queryChoices(0, m.source.Len())
+	if available > len(choices) {
+		choices, available = m.queryChoices(0, available)
+	}
+
+	return choices, available
+}
+
+// queryChoices returns up to limit choices that match the current filter
+// starting at the given offset as well as the total number of matching
+// choices.
+func (m *Model[T]) queryChoices(offset int, limit int) ([]*Choice[T], int) {
+	filterText := m.filterText()
+	if filterText == "" {
+		return m.source.Range(offset, limit), m.source.Len()
+	}
+
+	choices, available := m.source.Query(filterText, offset, limit)
+
+	return m.withCreateChoice(choices, available, offset, limit)
+}
+
+// filterText returns the current filter text or an empty string if filtering
+// is disabled.
+func (m *Model[T]) filterText() string {
+	if !m.isFilterEnabled() {
+		return ""
+	}
+
+	return m.filterInput.Value()
+}
+
+// isFilterEnabled returns true if either Filter or ScoreFilter is configured.
+func (m *Model[T]) isFilterEnabled() bool {
+	return m.Filter != nil || m.ScoreFilter != nil
+}
+
+// matchesFilter decides whether a choice matches the filter text. It is used
+// to filter the choices of selections that are created with New as well as
+// tree selections.
+func (m *Model[T]) matchesFilter(filterText string, choice *Choice[T]) bool {
+	if m.isRegexFilterActive() {
+		return m.matchesRegexFilter(choice)
+	}
+
+	if m.ScoreFilter != nil {
+		_, ok := m.ScoreFilter(filterText, choice)
+
+		return ok
+	}
+
+	return m.Filter == nil || m.Filter(filterText, choice)
+}
+
+// scoreFilter ranks the choices using the ScoreFilter unless the filter input
+// is in regex mode, in which case the matching choices keep their order.
+func (m *Model[T]) scoreFilter(filterText string, choice *Choice[T]) (int, bool) {
+	if m.isRegexFilterActive() {
+		return 0, m.matchesRegexFilter(choice)
+	}
+
+	return m.ScoreFilter(filterText, choice)
+}
+
+func (m *Model[T]) updateMatchPositions(choices []*Choice[T]) {
+	filterText := m.filterText()
+
+	for _, choice := range choices {
+		choice.matchPositions = nil
+
+		switch {
+		case m.MatchPositions == nil || filterText == "":
+		case m.isRegexFilterActive():
+			choice.matchPositions = m.regexMatchPositions(choice)
+		default:
+			choice.matchPositions = m.MatchPositions(filterText, choice)
 		}
+	}
+}
+
+// filteredChoices returns all choices that match the current filter regardless
+// of pagination.
+func (m *Model[T]) filteredChoices() []*Choice[T] {
+	choices, _ := m.queryAllChoices()
 
-		available++
+	return choices
+}
 
-		if m.PageSize > 0 && (len(choices) >= m.PageSize || ignored < m.scrollOffset) {
-			ignored++
+// allChoices returns all choices if they are available without querying the
+// choice source, which is the case for selections that are created with New.
+func (m *Model[T]) allChoices() []*Choice[T] {
+	src, ok := m.source.(*sliceSource[T])
+	if !ok {
+		return nil
+	}
 
+	return src.choices
+}
+
+// isCustomSource returns true if the choices are provided by a choice source
+// that was passed to NewFromSource.
+func (m *Model[T]) isCustomSource() bool {
+	switch m.source.(type) {
+	case *sliceSource[T], *treeSource[T]:
+		return false
+	default:
+		return true
+	}
+}
+
+// defaultSourcePageSize is the page size for custom choice sources until the
+// terminal size is known if no PageSize is configured.
+const defaultSourcePageSize = 100
+
+// initialChoiceBatchSize is the number of choices that are requested at once
+// from the choice source while searching for the InitialChoice.
+const initialChoiceBatchSize = 1000
+
+// initialPosition returns the position of the choice that is highlighted when
+// the prompt opens according to InitialChoice or InitialIndex.
+func (m *Model[T]) initialPosition() int {
+	if m.InitialChoice == nil {
+		return m.InitialIndex
+	}
+
+	for offset := 0; offset < m.source.Len(); offset += initialChoiceBatchSize {
+		for i, choice := range m.source.Range(offset, initialChoiceBatchSize) {
+			if m.InitialChoice(choice.Value) {
+				return offset + i
+			}
+		}
+	}
+
+	// the InitialChoice may still be streamed
+	m.awaitingInitialChoice = m.ChoiceStream != nil
+
+	return 0
+}
+
+// userInteracted discards the state that only lasts until the user interacts
+// with the prompt: the streamed InitialChoice is no longer awaited such that it
+// does not move the cursor and the error of the last attempt to change the
+// choices of a custom choice source is dismissed.
+func (m *Model[T]) userInteracted() {
+	m.awaitingInitialChoice = false
+	m.choicesErr = nil
+}
+
+// highlightInitialChoice highlights the first of the given choices that
+// satisfies InitialChoice if it was not found among the choices that were
+// available when the prompt was opened and the cursor was not moved since.
+func (m *Model[T]) highlightInitialChoice(choices []*Choice[T]) {
+	if !m.awaitingInitialChoice {
+		return
+	}
+
+	for _, choice := range choices {
+		if !m.InitialChoice(choice.Value) {
 			continue
 		}
 
-		choices = append(choices, choice)
+		position, ok := m.choicePosition(choice)
+		if ok {
+			m.awaitingInitialChoice = false
+			m.moveToPosition(position)
+			m.ensureSelectable()
+		}
+
+		return
 	}
+}
 
-	return choices, available
+// currentPosition returns the position of the highlighted choice among all
+// choices that match the current filter.
+func (m *Model[T]) currentPosition() int {
+	return m.scrollOffset + m.currentIdx
+}
+
+// highlightedChoice returns the currently highlighted choice or nil if no
+// choice is displayed.
+func (m *Model[T]) highlightedChoice() *Choice[T] {
+	if m.currentIdx < 0 || m.currentIdx >= len(m.currentChoices) {
+		return nil
+	}
+
+	return m.currentChoices[m.currentIdx]
+}
+
+// choicePositioner is implemented by the choice sources of this package which
+// can look up the position of a choice among the choices that match the
+// filter text.
+type choicePositioner[T any] interface {
+	position(filterText string, choice *Choice[T]) (int, bool)
+}
+
+// choicePosition returns the position of the given choice among all choices
+// that match the current filter. The position cannot be determined for custom
+// choice sources.
+func (m *Model[T]) choicePosition(choice *Choice[T]) (int, bool) {
+	if choice != nil && choice.create {
+		return m.createEntryPosition()
+	}
+
+	src, ok := m.source.(choicePositioner[T])
+	if !ok || choice == nil {
+		return 0, false
+	}
+
+	return src.position(m.filterText(), choice)
+}
+
+// moveToPosition highlights the choice at the given position among all
+// choices that match the current filter and scrolls such that it is visible.
+func (m *Model[T]) moveToPosition(position int) {
+	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+
+	position = max(0, min(position, m.availableChoices-1))
+
+	// the page is scrolled by whole rows in grid mode
+	rowLength := m.rowLength()
+	row, offsetRow, pageRows := position/rowLength, m.scrollOffset/rowLength, m.PageSize/rowLength
+
+	switch {
+	case m.PageSize <= 0:
+		offsetRow = 0
+	case row < offsetRow:
+		offsetRow = row
+	case row >= offsetRow+pageRows:
+		offsetRow = row - pageRows + 1
+	}
+
+	rows := (m.availableChoices + rowLength - 1) / rowLength
+	m.scrollOffset = max(0, min(offsetRow, rows-pageRows)) * rowLength
+	m.currentIdx = position - m.scrollOffset
+	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+}
+
+// isGroupStart returns true if the previously displayed choice does not belong
+// to the same group as the choice at the given index of the current page.
+// Ungrouped choices only start a new, unlabeled section if they follow a group.
+func (m *Model[T]) isGroupStart(idx int) bool {
+	if idx < 0 || idx >= len(m.currentChoices) {
+		return false
+	}
+
+	if idx == 0 {
+		return m.currentChoices[idx].Group != ""
+	}
+
+	return m.currentChoices[idx-1].Group != m.currentChoices[idx].Group
 }
 
 func (m *Model[T]) canScrollDown() bool {
@@ -415,7 +1060,7 @@ func (m *Model[T]) canScrollDown() bool {
 		return false
 	}
 
-	if m.scrollOffset+m.PageSize >= len(m.choices) {
+	if m.scrollOffset+m.PageSize >= m.availableChoices {
 		return false
 	}
 
@@ -427,79 +1072,152 @@ func (m *Model[T]) canScrollUp() bool {
 }
 
 func (m *Model[T]) cursorDown() {
-	if m.currentIdx == len(m.currentChoices)-1 {
-		if m.canScrollDown() {
-			m.scrollDown()
-		} else if m.LoopCursor {
-			m.scrollToTop()
+	position, ok := m.nextSelectablePosition(m.currentPosition(), m.rowLength())
+	if ok {
+		m.moveToPosition(position)
 
-			return
-		}
+		return
 	}
 
-	m.currentIdx = min(len(m.currentChoices)-1, m.currentIdx+1)
+	// in grid mode, the last row may be shorter than the others
+	lastPosition := m.availableChoices - 1
+	if !m.LoopCursor && m.currentPosition()/m.rowLength() < lastPosition/m.rowLength() {
+		choice := m.choiceAtPosition(lastPosition)
+		if choice != nil && !choice.Disabled {
+			m.moveToPosition(lastPosition)
+		}
+	}
 }
 
 func (m *Model[T]) cursorUp() {
-	if m.currentIdx == 0 {
-		if m.canScrollUp() {
-			m.scrollUp()
-		} else if m.LoopCursor {
-			m.scrollToBottom()
+	position, ok := m.nextSelectablePosition(m.currentPosition(), -m.rowLength())
+	if ok {
+		m.moveToPosition(position)
+	}
+}
 
-			return
+// nextSelectablePosition searches for the next choice in the given direction
+// that is not disabled and returns its position among all choices that match
+// the current filter. If LoopCursor is enabled, the search wraps around. The
+// choices are queried page by page rather than one by one.
+func (m *Model[T]) nextSelectablePosition(position int, direction int) (int, bool) {
+	var (
+		batch       []*Choice[T]
+		batchOffset int
+	)
+
+	for i := 0; i < m.availableChoices; i++ {
+		position += direction
+
+		if position < 0 || position >= m.availableChoices {
+			if !m.LoopCursor {
+				return 0, false
+			}
+
+			position = (position + m.availableChoices) % m.availableChoices
 		}
-	}
 
-	m.currentIdx = max(0, m.currentIdx-1)
-}
+		if position < batchOffset || position >= batchOffset+len(batch) {
+			batchOffset, batch = m.choicesAround(position, direction)
+		}
 
-func (m *Model[T]) scrollDown() {
-	if m.PageSize <= 0 || m.scrollOffset+m.PageSize >= m.availableChoices {
-		return
+		if position < batchOffset || position >= batchOffset+len(batch) {
+			continue
+		}
+
+		if !batch[position-batchOffset].Disabled {
+			return position, true
+		}
 	}
 
-	m.currentIdx = max(0, m.currentIdx-1)
-	m.scrollOffset++
-	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+	return 0, false
 }
 
-func (m *Model[T]) scrollToBottom() {
-	m.currentIdx = len(m.currentChoices) - 1
+// choicesAround returns a page of choices starting at the returned offset
+// which includes the given position and extends in the given direction such
+// that consecutive positions can be inspected without querying each one of
+// them.
+func (m *Model[T]) choicesAround(position int, direction int) (int, []*Choice[T]) {
+	if position >= m.scrollOffset && position-m.scrollOffset < len(m.currentChoices) {
+		return m.scrollOffset, m.currentChoices
+	}
 
-	if m.PageSize <= 0 || m.availableChoices < m.PageSize {
-		return
+	pageSize := max(1, m.PageSize)
+
+	offset := position
+	if direction < 0 {
+		offset = max(0, position-pageSize+1)
 	}
 
-	m.scrollOffset = m.availableChoices - m.PageSize
-	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+	choices, _ := m.queryChoices(offset, pageSize)
+
+	return offset, choices
 }
 
-func (m *Model[T]) scrollUp() {
-	if m.PageSize <= 0 || m.scrollOffset <= 0 {
+// ensureSelectable moves the cursor to the closest choice that is not disabled
+// in case the highlighted choice is disabled, preferring choices below it.
+func (m *Model[T]) ensureSelectable() {
+	choice := m.choiceAtPosition(m.currentPosition())
+	if choice == nil || !choice.Disabled {
 		return
 	}
 
-	m.currentIdx = min(len(m.currentChoices)-1, m.currentIdx+1)
-	m.scrollOffset--
-	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+	loopCursor := m.LoopCursor
+	m.LoopCursor = false
+
+	defer func() { m.LoopCursor = loopCursor }()
+
+	for _, direction := range []int{1, -1} {
+		position, ok := m.nextSelectablePosition(m.currentPosition(), direction)
+		if ok {
+			m.moveToPosition(position)
+
+			return
+		}
+	}
 }
 
-func (m *Model[T]) scrollToTop() {
-	m.currentIdx = 0
+// choiceAtPosition returns the choice at the given position among all choices
+// that match the current filter.
+func (m *Model[T]) choiceAtPosition(position int) *Choice[T] {
+	if position >= m.scrollOffset && position-m.scrollOffset < len(m.currentChoices) {
+		return m.currentChoices[position-m.scrollOffset]
+	}
+
+	choices, _ := m.queryChoices(position, 1)
+	if len(choices) == 0 {
+		return nil
+	}
 
-	if m.PageSize <= 0 || m.availableChoices < m.PageSize {
+	return choices[0]
+}
+
+func (m *Model[T]) scrollDown() {
+	if m.PageSize <= 0 || m.scrollOffset+m.PageSize >= m.availableChoices {
 		return
 	}
 
-	m.scrollOffset = 0
+	// the highlighted choice stays highlighted unless it is scrolled out of view
+	if m.currentIdx >= m.rowLength() {
+		m.currentIdx -= m.rowLength()
+	}
+
+	m.scrollOffset += m.rowLength()
 	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
+	m.currentIdx = min(m.currentIdx, len(m.currentChoices)-1)
 }
 
-func (m *Model[T]) reindexChoices() {
-	for i, choice := range m.choices {
-		choice.idx = i
+func (m *Model[T]) scrollUp() {
+	if m.PageSize <= 0 || m.scrollOffset <= 0 {
+		return
 	}
+
+	if m.currentIdx+m.rowLength() < len(m.currentChoices) {
+		m.currentIdx += m.rowLength()
+	}
+
+	m.scrollOffset = max(0, m.scrollOffset-m.rowLength())
+	m.currentChoices, m.availableChoices = m.filteredAndPagedChoices()
 }
 
 func max(a, b int) int {
@@ -2,10 +2,13 @@ package selection_test
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/erikgeiser/promptkit"
 	"github.com/erikgeiser/promptkit/selection"
 	"github.com/erikgeiser/promptkit/test"
@@ -199,7 +202,8 @@ func TestNoFilter(t *testing.T) {
 	m.PageSize = 2
 	m.ColorProfile = termenv.TrueColor
 
-	inputs := append(test.MsgsFromText("CC"), tea.KeyDown)
+	// typed text does not filter the choices, it only jumps to matching ones
+	inputs := append(test.MsgsFromText("XX"), tea.KeyDown)
 	test.Run(t, m, inputs...)
 	assertNoError(t, m)
 	test.AssertGoldenView(t, m, "no_filter.golden")
@@ -390,21 +394,1046 @@ func TestLoopCursorBottomToTopPaged(t *testing.T) {
 	test.AssertGoldenView(t, m, "loop_bottom_to_top_paged.golden")
 }
 
-func getChoice[T any](tb testing.TB, m *selection.Model[T]) T {
-	tb.Helper()
+func TestResizePageSize(t *testing.T) {
+	t.Parallel()
 
-	v, err := m.Value()
-	if err != nil {
-		tb.Fatalf("value: %v", err)
+	choices := make([]string, 5000)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("choice%d", i)
 	}
 
-	return v
+	renders := 0
+
+	m := selection.NewModel(selection.New("foo:", choices))
+	m.ColorProfile = termenv.TrueColor
+	m.Template += "{{ countRender }}"
+	m.ExtendedTemplateFuncs = map[string]interface{}{
+		"countRender": func() string {
+			renders++
+
+			return ""
+		},
+	}
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 80, Height: 10})
+	assertNoError(t, m)
+
+	if renders > 5 {
+		t.Errorf("resizing rendered the view %d times", renders)
+	}
+
+	if m.PageSize != 6 {
+		t.Errorf("unexpected page size %d, expected 6", m.PageSize)
+	}
+
+	test.AssertGoldenView(t, m, "resize.golden")
+
+	if height := lipgloss.Height(m.View()); height >= 10 {
+		t.Errorf("view with height %d does not fit the terminal", height)
+	}
 }
 
-func assertNoError[T any](tb testing.TB, m *selection.Model[T]) {
-	tb.Helper()
+func TestResizePageSizeUnevenChoiceHeights(t *testing.T) {
+	t.Parallel()
 
-	if m.Err != nil {
-		tb.Fatalf("model contains error: %v", m.Err)
+	m := selection.NewModel(selection.New("foo:", []string{
+		"a", "b", "looooong choice", "c", "d",
+	}))
+	m.ColorProfile = termenv.TrueColor
+	m.WrapMode = promptkit.WordWrap
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 12, Height: 9})
+	assertNoError(t, m)
+
+	if m.PageSize != 2 {
+		t.Errorf("unexpected page size %d, expected 2", m.PageSize)
+	}
+
+	if height := lipgloss.Height(m.View()); height >= 9 {
+		t.Errorf("view with height %d does not fit the terminal", height)
+	}
+}
+
+func TestResizeKeepsPosition(t *testing.T) {
+	t.Parallel()
+
+	choices := make([]string, 20)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("choice%d", i)
+	}
+
+	m := selection.NewModel(selection.New("foo:", choices))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 80, Height: 12})
+	assertNoError(t, m)
+
+	for i := 0; i < 10; i++ {
+		test.Update(t, m, tea.KeyDown)
+	}
+
+	test.Update(t, m, tea.WindowSizeMsg{Width: 80, Height: 7})
+	test.AssertGoldenView(t, m, "resize_shrink.golden")
+
+	if choice := getChoice(t, m); choice != "choice10" {
+		t.Errorf("shrinking moved the cursor to %q instead of choice10", choice)
+	}
+
+	test.Update(t, m, tea.WindowSizeMsg{Width: 80, Height: 20})
+	test.AssertGoldenView(t, m, "resize_grow.golden")
+
+	if choice := getChoice(t, m); choice != "choice10" {
+		t.Errorf("growing moved the cursor to %q instead of choice10", choice)
+	}
+}
+
+func TestResizeKeepsPositionFiltered(t *testing.T) {
+	t.Parallel()
+
+	choices := make([]string, 30)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("choice%d", i)
+	}
+
+	m := selection.NewModel(selection.New("foo:", choices))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 80, Height: 8})
+	assertNoError(t, m)
+
+	for _, msg := range append(test.MsgsFromText("2"),
+		tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyDown) {
+		test.Update(t, m, msg)
+	}
+
+	if choice := getChoice(t, m); choice != "choice22" {
+		t.Fatalf("unexpected choice %q before resizing, expected choice22", choice)
+	}
+
+	test.Update(t, m, tea.WindowSizeMsg{Width: 80, Height: 6})
+	test.AssertGoldenView(t, m, "resize_filtered.golden")
+
+	if choice := getChoice(t, m); choice != "choice22" {
+		t.Errorf("resizing moved the cursor to %q instead of choice22", choice)
+	}
+}
+
+func TestFilterKeepsHighlightedChoice(t *testing.T) {
+	t.Parallel()
+
+	choices := make([]string, 30)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("choice%d", i)
+	}
+
+	m := selection.NewModel(selection.New("foo:", choices))
+	m.PageSize = 5
+	m.ColorProfile = termenv.TrueColor
+
+	inputs := make([]tea.Msg, 0, 13)
+	for i := 0; i < 12; i++ {
+		inputs = append(inputs, tea.KeyDown)
+	}
+
+	test.Run(t, m, append(inputs, test.KeyMsg('1'))...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "filter_keeps_highlighted.golden")
+
+	if choice := getChoice(t, m); choice != "choice12" {
+		t.Errorf("filtering moved the cursor to %q instead of choice12", choice)
+	}
+
+	// the highlighted choice no longer matches
+	test.Update(t, m, test.KeyMsg('5'))
+
+	if choice := getChoice(t, m); choice != "choice15" {
+		t.Errorf("unexpected choice %q, expected choice15", choice)
+	}
+
+	test.Update(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+
+	if choice := getChoice(t, m); choice != "choice15" {
+		t.Errorf("changing the filter moved the cursor to %q instead of choice15", choice)
+	}
+
+	test.Update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+
+	if choice := getChoice(t, m); choice != "choice15" {
+		t.Errorf("clearing the filter moved the cursor to %q instead of choice15", choice)
+	}
+}
+
+func TestNewFromChoicesCopiesChoices(t *testing.T) {
+	t.Parallel()
+
+	choices := make([]*selection.Choice[string], 0, 3)
+	choices = append(choices, selection.NewChoice(0, "b"), selection.NewChoice(1, "a"))
+
+	s := selection.NewFromChoices("foo:", choices)
+	s.Columns = []selection.Column[string]{{Header: "NAME", Value: func(v string) string { return v }}}
+	s.SortColumn = "NAME"
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, selection.AddChoicesMsg[string]{Choices: []string{"c"}})
+	assertNoError(t, m)
+
+	if choices[0].Value != "b" || choices[1].Value != "a" || choices[:3][2] != nil {
+		t.Errorf("the choices passed to NewFromChoices were modified")
+	}
+}
+
+func TestDisabledChoices(t *testing.T) {
+	t.Parallel()
+
+	choices := []*selection.Choice[string]{
+		selection.NewChoice(0, "eu-west"), selection.NewChoice(1, "eu-central"), selection.NewChoice(2, "us-east"),
+		selection.NewChoice(3, "us-west"), selection.NewChoice(4, "ap-south"),
+	}
+	choices[0].Disabled = true
+	choices[0].DisabledReason = "quota exceeded"
+	choices[2].Disabled = true
+	choices[4].Disabled = true
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", choices))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "disabled_initial.golden")
+
+	if choice := getChoice(t, m); choice != "eu-central" {
+		t.Errorf("unexpected initial choice: %q, expected eu-central", choice)
+	}
+
+	test.Update(t, m, tea.KeyDown)
+	test.AssertGoldenView(t, m, "disabled_skip.golden")
+
+	if choice := getChoice(t, m); choice != "us-west" {
+		t.Errorf("unexpected choice: %q, expected us-west", choice)
+	}
+
+	// the last choice is disabled, so the cursor cannot move further down
+	test.Update(t, m, tea.KeyDown)
+
+	if choice := getChoice(t, m); choice != "us-west" {
+		t.Errorf("unexpected choice: %q, expected us-west", choice)
+	}
+
+	test.Update(t, m, tea.KeyUp)
+
+	if choice := getChoice(t, m); choice != "eu-central" {
+		t.Errorf("unexpected choice: %q, expected eu-central", choice)
+	}
+}
+
+func TestDisabledChoicesLoopCursor(t *testing.T) {
+	t.Parallel()
+
+	choices := []*selection.Choice[string]{
+		selection.NewChoice(0, "a"), selection.NewChoice(1, "b"), selection.NewChoice(2, "c"),
+	}
+	choices[2].Disabled = true
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", choices))
+	m.LoopCursor = true
+
+	test.Run(t, m, tea.KeyUp)
+	assertNoError(t, m)
+
+	if choice := getChoice(t, m); choice != "b" {
+		t.Errorf("unexpected choice: %q, expected b", choice)
+	}
+}
+
+func TestDisabledChoiceCannotBeSelected(t *testing.T) {
+	t.Parallel()
+
+	choices := []*selection.Choice[string]{selection.NewChoice(0, "eu-west"), selection.NewChoice(1, "ap-south")}
+	choices[1].Disabled = true
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", choices))
+	m.ColorProfile = termenv.TrueColor
+
+	// the filter only leaves a disabled choice, so the prompt does not quit
+	test.Run(t, m, test.MsgsFromText("ap")...)
+
+	if cmd := test.Update(t, m, tea.KeyEnter); cmd != nil {
+		t.Errorf("unexpected command after selecting a disabled choice")
+	}
+
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "disabled_reject.golden")
+}
+
+func TestGroups(t *testing.T) {
+	t.Parallel()
+
+	choices := []*selection.Choice[string]{
+		selection.NewChoice(0, "prod-eu"), selection.NewChoice(1, "prod-us"), selection.NewChoice(2, "staging-eu"),
+		selection.NewChoice(3, "staging-us"), selection.NewChoice(4, "sandbox"),
+	}
+	choices[0].Group, choices[1].Group = "Production", "Production"
+	choices[2].Group, choices[3].Group = "Staging", "Staging"
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", choices))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, tea.KeyDown, tea.KeyDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "groups.golden")
+
+	if choice := getChoice(t, m); choice != "staging-eu" {
+		t.Errorf("unexpected choice: %v, expected staging-eu", choice)
+	}
+}
+
+func TestGroupsFiltered(t *testing.T) {
+	t.Parallel()
+
+	choices := []*selection.Choice[string]{
+		selection.NewChoice(0, "prod-eu"), selection.NewChoice(1, "prod-us"), selection.NewChoice(2, "staging-eu"),
+		selection.NewChoice(3, "staging-us"), selection.NewChoice(4, "sandbox"),
+	}
+	choices[0].Group, choices[1].Group = "Production", "Production"
+	choices[2].Group, choices[3].Group = "Staging", "Staging"
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", choices))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, append(test.MsgsFromText("us"), tea.KeyDown)...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "groups_filtered.golden")
+
+	if choice := getChoice(t, m); choice != "staging-us" {
+		t.Errorf("unexpected choice: %v, expected staging-us", choice)
+	}
+}
+
+func TestGroupsPaged(t *testing.T) {
+	t.Parallel()
+
+	choices := []*selection.Choice[string]{
+		selection.NewChoice(0, "prod-eu"), selection.NewChoice(1, "prod-us"), selection.NewChoice(2, "staging-eu"),
+		selection.NewChoice(3, "staging-us"), selection.NewChoice(4, "sandbox"),
+	}
+	choices[0].Group, choices[1].Group = "Production", "Production"
+	choices[2].Group, choices[3].Group = "Staging", "Staging"
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", choices))
+	m.ColorProfile = termenv.TrueColor
+	m.PageSize = 2
+
+	test.Run(t, m, tea.KeyDown, tea.KeyDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "groups_paged.golden")
+}
+
+func TestHotkeys(t *testing.T) {
+	t.Parallel()
+
+	deploy, rollback, status := selection.NewChoice(0, "deploy"), selection.NewChoice(1, "rollback"), selection.NewChoice(2, "status")
+	deploy.Hotkey, rollback.Hotkey, status.Hotkey = "d", "r", "ctrl+s"
+
+	s := selection.NewFromChoices("foo:", []*selection.Choice[string]{deploy, rollback, status})
+	s.Filter = nil
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "hotkeys.golden")
+
+	cmd := test.Update(t, m, test.KeyMsg('r'))
+	if cmd == nil {
+		t.Fatalf("pressing a hotkey did not quit the prompt")
+	}
+
+	if choice := getChoice(t, m); choice != "rollback" {
+		t.Errorf("unexpected choice: %q, expected rollback", choice)
+	}
+}
+
+func TestHotkeysFilterInput(t *testing.T) {
+	t.Parallel()
+
+	deploy := selection.NewChoice(0, "deploy")
+	deploy.Hotkey = "d"
+
+	status := selection.NewChoice(1, "status")
+	status.Hotkey = "s"
+
+	m := selection.NewModel(selection.NewFromChoices("foo:",
+		[]*selection.Choice[string]{deploy, status, selection.NewChoice(2, "staging-db")}))
+
+	// printable hotkeys are typed into the focused filter input
+	test.Run(t, m, test.MsgsFromText("sta")...)
+	assertNoError(t, m)
+
+	if view := m.View(); !strings.Contains(view, "staging-db") {
+		t.Fatalf("typing the filter text selected a choice by its hotkey:\n%s", view)
+	}
+}
+
+func TestHotkeysFiltered(t *testing.T) {
+	t.Parallel()
+
+	deploy, rollback, status := selection.NewChoice(0, "deploy"), selection.NewChoice(1, "rollback"), selection.NewChoice(2, "status")
+	deploy.Hotkey, rollback.Hotkey, status.Hotkey = "d", "r", "ctrl+s"
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", []*selection.Choice[string]{deploy, rollback, status}))
+
+	// printable hotkeys are typed into the filter input such that no choice
+	// matches
+	test.Run(t, m, test.MsgsFromText("er")...)
+	assertNoError(t, m)
+
+	if choice, err := m.Value(); err == nil {
+		t.Fatalf("unexpected choice %q for filter text that matches no choice", choice)
+	}
+
+	// the filter is cleared if it hides the choice of the hotkey
+	cmd := test.Update(t, m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatalf("pressing a hotkey did not quit the prompt")
+	}
+
+	if choice := getChoice(t, m); choice != "status" {
+		t.Errorf("unexpected choice: %q, expected status", choice)
+	}
+}
+
+func TestHotkeysMulti(t *testing.T) {
+	t.Parallel()
+
+	deploy, rollback, status := selection.NewChoice(0, "deploy"), selection.NewChoice(1, "rollback"), selection.NewChoice(2, "status")
+	deploy.Hotkey, rollback.Hotkey, status.Hotkey = "d", "r", "ctrl+s"
+
+	s := selection.NewMultiFromChoices("foo:", []*selection.Choice[string]{deploy, rollback, status})
+	s.Filter = nil
+
+	m := selection.NewMultiModel(s)
+
+	test.Run(t, m, test.KeyMsg('r'), tea.KeyMsg{Type: tea.KeyCtrlS}, tea.KeyEnter)
+	assertValues(t, m, []string{"rollback", "status"})
+}
+
+func TestHotkeyConflicts(t *testing.T) {
+	t.Parallel()
+
+	choice := selection.NewChoice(0, "deploy")
+	choice.Hotkey = "enter"
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", []*selection.Choice[string]{choice}))
+	m.Init()
+
+	assertValueError(t, m, `hotkey "enter" conflicts with the select key`)
+
+	deploy, rollback := selection.NewChoice(0, "deploy"), selection.NewChoice(1, "rollback")
+	deploy.Hotkey, rollback.Hotkey = "r", "r"
+
+	m = selection.NewModel(selection.NewFromChoices("foo:", []*selection.Choice[string]{deploy, rollback}))
+	m.Init()
+
+	assertValueError(t, m, `hotkey "r" is assigned to multiple choices`)
+
+	choice = selection.NewChoice(0, "deploy")
+	choice.Hotkey = "tab"
+
+	mm := selection.NewMultiModel(selection.NewMultiFromChoices("foo:", []*selection.Choice[string]{choice}))
+	mm.Init()
+
+	_, err := mm.Values()
+	if err == nil || !strings.Contains(err.Error(), `hotkey "tab" conflicts with the toggle key`) {
+		t.Errorf("unexpected error for hotkey that conflicts with multi-selection: %v", err)
+	}
+}
+
+func TestHotkeyQuickSelectConflict(t *testing.T) {
+	t.Parallel()
+
+	choice := selection.NewChoice(0, "deploy")
+	choice.Hotkey = "d"
+
+	sel := selection.NewFromChoices("foo:", []*selection.Choice[string]{choice})
+	sel.QuickSelect = true
+
+	m := selection.NewModel(sel)
+	m.Init()
+
+	assertValueError(t, m, `hotkey "d" conflicts with a quick-select label`)
+}
+
+func TestHotkeyGridKeys(t *testing.T) {
+	t.Parallel()
+
+	choice := selection.NewChoice(0, "deploy")
+	choice.Hotkey = "left"
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", []*selection.Choice[string]{choice}))
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	choice = selection.NewChoice(0, "deploy")
+	choice.Hotkey = "left"
+
+	sel := selection.NewFromChoices("foo:", []*selection.Choice[string]{choice})
+	sel.Grid = true
+
+	m = selection.NewModel(sel)
+	m.Init()
+
+	assertValueError(t, m, `hotkey "left" conflicts with the left key`)
+}
+
+func TestHotkeyOfRemovedChoice(t *testing.T) {
+	t.Parallel()
+
+	deploy, status := selection.NewChoice(0, "deploy"), selection.NewChoice(1, "status")
+	deploy.Hotkey, status.Hotkey = "d", "ctrl+s"
+
+	m := selection.NewModel(selection.NewFromChoices("foo:", []*selection.Choice[string]{deploy, status}))
+	test.Run(t, m, selection.RemoveChoicesMsg[string]{
+		Remove: func(c *selection.Choice[string]) bool {
+			return c.Value == "status"
+		},
+	})
+	assertNoError(t, m)
+
+	cmd := test.Update(t, m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd != nil {
+		t.Fatalf("the hotkey of a removed choice returned a command")
+	}
+
+	if choice := getChoice(t, m); choice != "deploy" {
+		t.Fatalf("unexpected choice: %q, expected deploy", choice)
+	}
+}
+
+func TestQuickSelect(t *testing.T) {
+	t.Parallel()
+
+	choices := make([]string, 15)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("choice%d", i)
+	}
+
+	s := selection.New("foo:", choices)
+	s.QuickSelect = true
+	s.PageSize = 12
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyPgDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "quick_select.golden")
+
+	cmd := test.Update(t, m, test.KeyMsg('b'))
+	if cmd == nil {
+		t.Fatalf("pressing a label did not quit the prompt")
+	}
+
+	if choice := getChoice(t, m); choice != "choice11" {
+		t.Errorf("unexpected choice: %q, expected choice11", choice)
+	}
+}
+
+func TestQuickSelectFilter(t *testing.T) {
+	t.Parallel()
+
+	choices := make([]string, 15)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("choice%d", i)
+	}
+
+	s := selection.New("foo:", choices)
+	s.QuickSelect = true
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	// digits go to the filter input once it is focused
+	test.Run(t, m, append(append([]tea.Msg{test.KeyMsg('/')}, test.MsgsFromText("1")...), tea.KeyEsc)...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "quick_select_filter.golden")
+
+	test.Update(t, m, test.KeyMsg('3'))
+
+	if choice := getChoice(t, m); choice != "choice11" {
+		t.Errorf("unexpected choice: %q, expected choice11", choice)
+	}
+}
+
+func TestQuickSelectMulti(t *testing.T) {
+	t.Parallel()
+
+	s := selection.NewMulti("foo:", []string{"a", "b", "c"})
+	s.QuickSelect = true
+
+	m := selection.NewMultiModel(s)
+
+	test.Run(t, m, test.MsgsFromText("31")...)
+	assertNoError(t, m.Model)
+
+	assertValues(t, m, []string{"a", "c"})
+}
+
+func TestInitialIndex(t *testing.T) {
+	t.Parallel()
+
+	regions := []string{"ap-1", "ap-2", "ap-3", "ap-4", "eu-1", "eu-2", "eu-3", "eu-4", "us-1", "us-2", "us-3", "us-4"}
+
+	s := selection.New("foo:", regions)
+	s.InitialIndex = 9
+	s.PageSize = 4
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "initial_index.golden")
+
+	if choice := getChoice(t, m); choice != "us-2" {
+		t.Errorf("unexpected initial choice: %q, expected us-2", choice)
+	}
+}
+
+func TestInitialChoice(t *testing.T) {
+	t.Parallel()
+
+	regions := []string{"ap-1", "ap-2", "eu-1", "eu-2", "eu-3", "us-1", "us-2"}
+
+	s := selection.New("foo:", regions)
+	s.InitialIndex = 6
+	s.InitialChoice = func(region string) bool { return region == "eu-3" }
+	s.PageSize = 4
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if choice := getChoice(t, m); choice != "eu-3" {
+		t.Errorf("unexpected initial choice: %q, expected eu-3", choice)
+	}
+
+	s = selection.New("foo:", regions)
+	s.InitialChoice = func(region string) bool { return region == "sa-1" }
+
+	m = selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if choice := getChoice(t, m); choice != "ap-1" {
+		t.Errorf("unexpected initial choice without match: %q, expected ap-1", choice)
+	}
+}
+
+func TestGrid(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{
+		"at", "be", "bg", "ch", "cy", "cz", "de", "dk", "ee", "es",
+		"fi", "fr", "gr", "hr", "hu", "ie", "it", "lt", "lu", "lv",
+	})
+	s.Grid = true
+	s.PageSize = 2
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 40, Height: 20}, tea.KeyRight, tea.KeyRight, tea.KeyDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "grid.golden")
+
+	if choice := getChoice(t, m); choice != "ee" {
+		t.Errorf("unexpected choice: %q, expected ee", choice)
+	}
+
+	test.Update(t, m, tea.KeyDown)
+	test.AssertGoldenView(t, m, "grid_scrolled.golden")
+
+	if choice := getChoice(t, m); choice != "hu" {
+		t.Errorf("unexpected choice: %q, expected hu", choice)
+	}
+
+	// the last row is shorter than the others
+	for _, key := range []tea.KeyType{tea.KeyLeft, tea.KeyRight, tea.KeyRight, tea.KeyDown} {
+		test.Update(t, m, key)
+	}
+
+	if choice := getChoice(t, m); choice != "lv" {
+		t.Errorf("unexpected choice: %q, expected lv", choice)
+	}
+
+	test.Update(t, m, tea.KeyUp)
+
+	if choice := getChoice(t, m); choice != "hr" {
+		t.Errorf("unexpected choice: %q, expected hr", choice)
+	}
+}
+
+func TestGridResize(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{
+		"at", "be", "bg", "ch", "cy", "cz", "de", "dk", "ee", "es",
+		"fi", "fr", "gr", "hr", "hu", "ie", "it", "lt", "lu", "lv",
+	})
+	s.Grid = true
+	s.PageSize = 2
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 40}, tea.KeyDown)
+	assertNoError(t, m)
+
+	if m.PageSize != 12 {
+		t.Errorf("unexpected page size %d for 2 rows with 6 columns", m.PageSize)
+	}
+
+	test.Update(t, m, tea.WindowSizeMsg{Width: 20})
+
+	if m.PageSize != 6 {
+		t.Errorf("unexpected page size %d for 2 rows with 3 columns", m.PageSize)
+	}
+
+	if choice := getChoice(t, m); choice != "de" {
+		t.Errorf("resizing moved the cursor to %q", choice)
+	}
+}
+
+func TestGridCellWidthOfAllChoices(t *testing.T) {
+	t.Parallel()
+
+	choices := make([]string, 1500)
+	for i := range choices {
+		choices[i] = "x"
+	}
+
+	choices[len(choices)-1] = "a-long-choice"
+
+	s := selection.New("foo:", choices)
+	s.Grid = true
+	s.Template = "{{ .GridCellWidth }}"
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	// the width of the last choice and the padding of the cell
+	if view := m.View(); view != "17" {
+		t.Errorf("unexpected grid cell width %s, expected 17", view)
+	}
+}
+
+func TestGridQuickSelect(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{
+		"at", "be", "bg", "ch", "cy", "cz", "de", "dk", "ee", "es",
+		"fi", "fr", "gr", "hr", "hu", "ie", "it", "lt", "lu", "lv",
+	})
+	s.Grid = true
+	s.QuickSelect = true
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 40, Height: 20})
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "grid_quick_select.golden")
+
+	cmd := test.Update(t, m, test.KeyMsg('c'))
+	if cmd == nil {
+		t.Fatalf("pressing a label did not quit the prompt")
+	}
+
+	if choice := getChoice(t, m); choice != "fr" {
+		t.Errorf("unexpected choice: %q, expected fr", choice)
+	}
+}
+
+func TestGridGroups(t *testing.T) {
+	t.Parallel()
+
+	choices := []*selection.Choice[string]{}
+
+	for i, name := range []string{"prod-eu", "prod-us", "prod-ch", "staging-eu", "sandbox"} {
+		choice := selection.NewChoice(i, name)
+		if i < 3 {
+			choice.Group = "Production"
+		} else if i < 4 {
+			choice.Group = "Staging"
+		}
+
+		choices = append(choices, choice)
+	}
+
+	s := selection.NewFromChoices("foo:", choices)
+	s.Grid = true
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 30, Height: 20}, tea.KeyDown, tea.KeyRight)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "grid_groups.golden")
+
+	if choice := getChoice(t, m); choice != "staging-eu" {
+		t.Errorf("unexpected choice: %q, expected staging-eu", choice)
+	}
+}
+
+func TestRegexFilter(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"web-01.eu", "web-02.eu", "web-10.us", "db-01.eu", "webcache.us"})
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, append([]tea.Msg{tea.KeyCtrlT}, test.MsgsFromText(`^WEB-\d+\.eu$`)...)...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "regex_filter.golden")
+
+	// toggling back interprets the filter text as plain text again
+	test.Update(t, m, tea.KeyMsg{Type: tea.KeyCtrlT})
+	assertNoError(t, m)
+
+	if _, err := m.Value(); err == nil {
+		t.Errorf("expected no choices to match the filter text literally")
+	}
+}
+
+func TestRegexFilterInvalid(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"web-01.eu", "web-02.eu", "web-10.us", "db-01.eu", "webcache.us"})
+	s.RegexFilter = true
+	// in regex mode, the positions are determined by the regular expression
+	s.MatchPositions = func(string, *selection.Choice[string]) []int { return nil }
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	// the choices that match web-0 stay visible while the group is incomplete
+	test.Run(t, m, test.MsgsFromText("web-0(")...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "regex_filter_invalid.golden")
+
+	if choice := getChoice(t, m); choice != "web-01.eu" {
+		t.Errorf("unexpected choice: %q, expected web-01.eu", choice)
+	}
+}
+
+func TestRegexFilterTree(t *testing.T) {
+	t.Parallel()
+
+	s := selection.NewTree("foo:", []*selection.TreeNode[string]{
+		selection.NewTreeNode("eu", selection.NewTreeNode("web-01"), selection.NewTreeNode("db-01")),
+		selection.NewTreeNode("us", selection.NewTreeNode("web-10")),
+	})
+	s.RegexFilter = true
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewTreeModel(s)
+
+	test.Run(t, m, test.MsgsFromText("^web-0")...)
+	assertPath(t, m, []string{"eu"})
+
+	test.Update(t, m, tea.KeyMsg{Type: tea.KeyDown})
+	assertPath(t, m, []string{"eu", "web-01"})
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []pod{
+		{name: "api-7f9c6d-x2x9k", status: "Running", age: 3 * time.Hour},
+		{name: "worker-5d8b7c-qq7lp", status: "CrashLoopBackOff", age: 12 * time.Minute},
+		{name: "db-0", status: "Running", age: 240 * time.Hour},
+		{name: "cache-6c4f8-mm2zt", status: "Pending", age: 45 * time.Second},
+	})
+	s.Columns = podColumns()
+	s.SortColumn = "AGE"
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "table.golden")
+
+	if choice := getChoice(t, m); choice.name != "worker-5d8b7c-qq7lp" {
+		t.Errorf("unexpected choice: %q, expected worker-5d8b7c-qq7lp", choice.name)
+	}
+
+	// filtering applies to all columns
+	for _, msg := range test.MsgsFromText("running") {
+		test.Update(t, m, msg)
+	}
+
+	test.AssertGoldenView(t, m, "table_filtered.golden")
+}
+
+func TestTableTruncated(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []pod{
+		{name: "api-7f9c6d-x2x9k", status: "Running", age: 3 * time.Hour},
+		{name: "worker-5d8b7c-qq7lp", status: "CrashLoopBackOff", age: 12 * time.Minute},
+		{name: "db-0", status: "Running", age: 240 * time.Hour},
+		{name: "cache-6c4f8-mm2zt", status: "Pending", age: 45 * time.Second},
+	})
+	s.Columns = podColumns()
+	s.SortColumn = "NAME"
+	s.SortDescending = true
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 36, Height: 20})
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "table_truncated.golden")
+}
+
+func TestTableUnknownSortColumn(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []pod{{name: "db-0", status: "Running", age: time.Hour}})
+	s.Columns = podColumns()
+	s.SortColumn = "READY"
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+
+	if m.Err == nil {
+		t.Fatalf("expected an error for an unknown sort column")
+	}
+}
+
+func TestTableTruncatedFiltered(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []pod{
+		{name: "api-7f9c6d-x2x9k", status: "Running", age: 3 * time.Hour},
+		{name: "worker-5d8b7c-qq7lp", status: "CrashLoopBackOff", age: 12 * time.Minute},
+		{name: "db-0", status: "Running", age: 240 * time.Hour},
+		{name: "cache-6c4f8-mm2zt", status: "Pending", age: 45 * time.Second},
+	})
+	s.Columns = podColumns()
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	// the name is truncated at this width, but the whole name is filtered
+	test.Run(t, m, append([]tea.Msg{tea.WindowSizeMsg{Width: 36, Height: 20}}, test.MsgsFromText("qq7lp")...)...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "table_truncated_filtered.golden")
+
+	if choice := getChoice(t, m); choice.name != "worker-5d8b7c-qq7lp" {
+		t.Errorf("unexpected choice: %q, expected worker-5d8b7c-qq7lp", choice.name)
+	}
+
+	// the padding between the columns is not matched
+	for _, msg := range append([]tea.Msg{tea.KeyEsc}, test.MsgsFromText("db-0  ")...) {
+		test.Update(t, m, msg)
+	}
+
+	if _, err := m.Value(); err == nil {
+		t.Errorf("filter matched the padding between the columns")
+	}
+}
+
+func TestTableViewDoesNotModifyChoices(t *testing.T) {
+	t.Parallel()
+
+	choices := []*selection.Choice[pod]{}
+	for i, p := range []pod{
+		{name: "api-7f9c6d-x2x9k", status: "Running", age: 3 * time.Hour},
+		{name: "worker-5d8b7c-qq7lp", status: "CrashLoopBackOff", age: 12 * time.Minute},
+	} {
+		choices = append(choices, selection.NewChoice(i, p))
+	}
+
+	var strs []string
+
+	s := selection.NewFromChoices("foo:", choices)
+	s.Columns = podColumns()
+	s.UnselectedChoiceStyle = func(c *selection.Choice[pod]) string {
+		// the choices may be read concurrently while the view is rendered
+		for i, str := range strs {
+			if choices[i].String != str {
+				t.Errorf("string of choice %d was changed to %q while rendering", i, choices[i].String)
+			}
+		}
+
+		return c.String
+	}
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown)
+	assertNoError(t, m)
+
+	for _, choice := range choices {
+		strs = append(strs, choice.String)
+	}
+
+	if view := m.View(); !strings.Contains(view, "CrashLoopBackOff") {
+		t.Errorf("table rows are not displayed:\n%s", view)
+	}
+}
+
+func getChoice[T any](tb testing.TB, m *selection.Model[T]) T {
+	tb.Helper()
+
+	v, err := m.Value()
+	if err != nil {
+		tb.Fatalf("value: %v", err)
+	}
+
+	return v
+}
+
+func assertNoError[T any](tb testing.TB, m *selection.Model[T]) {
+	tb.Helper()
+
+	if m.Err != nil {
+		tb.Fatalf("model contains error: %v", m.Err)
+	}
+}
+
+func assertValueError[T any](tb testing.TB, m *selection.Model[T], expected string) {
+	tb.Helper()
+
+	_, err := m.Value()
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		tb.Errorf("unexpected error: %v, expected %q", err, expected)
+	}
+}
+
+type pod struct {
+	name   string
+	status string
+	age    time.Duration
+}
+
+func podColumns() []selection.Column[pod] {
+	return []selection.Column[pod]{
+		{Header: "NAME", Value: func(p pod) string { return p.name }},
+		{Header: "STATUS", Value: func(p pod) string { return p.status }},
+		{
+			Header: "AGE",
+			Value:  func(p pod) string { return p.age.String() },
+			Less:   func(a pod, b pod) bool { return a.age < b.age },
+		},
 	}
 }
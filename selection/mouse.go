@@ -0,0 +1,164 @@
+package selection
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/ansi"
+)
+
+// updateMouse handles mouse events if EnableMouse is set. Clicking a choice
+// highlights it and clicking the highlighted choice again selects it. The
+// mouse wheel scrolls the choices.
+func (m *Model[T]) updateMouse(msg tea.MouseMsg) tea.Cmd {
+	if !m.EnableMouse {
+		return nil
+	}
+
+	// events other than those of the left button, which are also sent while
+	// dragging, mean that the button was released even if the release event
+	// itself was lost
+	if msg.Type != tea.MouseLeft {
+		m.mousePressed = false
+	}
+
+	switch msg.Type {
+	case tea.MouseWheelDown:
+		m.scrollDown()
+		m.ensureSelectable()
+	case tea.MouseWheelUp:
+		m.scrollUp()
+		m.ensureSelectable()
+	case tea.MouseLeft:
+		idx, ok := m.clickedChoiceIndex(msg)
+		if !ok {
+			return nil
+		}
+
+		if idx != m.currentIdx {
+			m.currentIdx = idx
+
+			return nil
+		}
+
+		return m.submit()
+	}
+
+	return nil
+}
+
+// clickedChoiceIndex returns the index of the displayed choice that was
+// clicked with the left mouse button unless it is disabled.
+func (m *Model[T]) clickedChoiceIndex(msg tea.MouseMsg) (int, bool) {
+	// with cell motion enabled, dragging the mouse also produces events for
+	// the left button that should not be interpreted as clicks
+	if m.mousePressed {
+		return 0, false
+	}
+
+	m.mousePressed = true
+
+	idx, ok := m.choiceIndexAt(msg.X, msg.Y)
+	if !ok || m.currentChoices[idx].Disabled {
+		return 0, false
+	}
+
+	return idx, true
+}
+
+// choiceMarkerFormat is the format of the invisible markers that
+// choiceIndexAt places around each displayed choice as it is rendered by the
+// Selected, Unselected and Disabled template functions. They contain the kind
+// of the marker and the index of the choice.
+const choiceMarkerFormat = "\x1b[65;%d;%dm"
+
+var choiceMarkerPattern = regexp.MustCompile(`\x1b\[65;(\d+);(\d+)m`)
+
+const (
+	choiceMarkerStart = 0
+	choiceMarkerEnd   = 1
+)
+
+// choiceIndexAt returns the index of the displayed choice that is rendered at
+// the given column and row of the view. As custom templates can render choices
+// in any way, the view is rendered once with zero-width escape sequences
+// around the output of the template functions that render each choice, which
+// reveal the rows and columns that each choice occupies.
+func (m *Model[T]) choiceIndexAt(column int, row int) (int, bool) {
+	if row < 0 || m.quitting {
+		return 0, false
+	}
+
+	m.markChoices = true
+	lines := strings.Split(m.View(), "\n")
+	m.markChoices = false
+
+	if row >= len(lines) {
+		return 0, false
+	}
+
+	// the choice that is still open at the start of the clicked row spans
+	// multiple rows
+	open := -1
+
+	for _, line := range lines[:row] {
+		for _, marker := range choiceMarkerPattern.FindAllStringSubmatch(line, -1) {
+			kind, idx := atoi(marker[1]), atoi(marker[2])
+
+			switch {
+			case kind == choiceMarkerStart:
+				open = idx
+			case idx == open:
+				open = -1
+			}
+		}
+	}
+
+	// in grid mode, the clicked choice is the last one that starts left of
+	// the clicked column
+	clicked := open
+	line := lines[row]
+
+	for _, loc := range choiceMarkerPattern.FindAllStringSubmatchIndex(line, -1) {
+		if atoi(line[loc[2]:loc[3]]) != choiceMarkerStart {
+			continue
+		}
+
+		if clicked < 0 || ansi.PrintableRuneWidth(line[:loc[0]]) <= column {
+			clicked = atoi(line[loc[4]:loc[5]])
+		}
+	}
+
+	if clicked < 0 || clicked >= len(m.currentChoices) {
+		return 0, false
+	}
+
+	return clicked, true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+
+	return n
+}
+
+// markChoice surrounds the rendered choice with markers that contain its
+// index among the displayed choices while the view is rendered to locate
+// clicked choices.
+func (m *Model[T]) markChoice(choice *Choice[T], rendered string) string {
+	if !m.markChoices {
+		return rendered
+	}
+
+	for idx, displayed := range m.renderedChoices {
+		if displayed == choice {
+			return fmt.Sprintf(choiceMarkerFormat, choiceMarkerStart, idx) + rendered +
+				fmt.Sprintf(choiceMarkerFormat, choiceMarkerEnd, idx)
+		}
+	}
+
+	return rendered
+}
@@ -0,0 +1,177 @@
+package selection_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/reflow/ansi"
+)
+
+func click(row int) []tea.Msg {
+	return []tea.Msg{
+		tea.MouseMsg{Type: tea.MouseLeft, Y: row},
+		tea.MouseMsg{Type: tea.MouseRelease, Y: row},
+	}
+}
+
+func TestMouse(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c", "d", "e"})
+	s.EnableMouse = true
+	s.PageSize = 3
+
+	m := selection.NewModel(s)
+
+	// the first two rows are the prompt and the filter input
+	test.Run(t, m, click(3)...)
+	assertNoError(t, m)
+
+	if choice := getChoice(t, m); choice != "b" {
+		t.Errorf("unexpected choice after click: %q, expected b", choice)
+	}
+
+	test.Update(t, m, tea.MouseMsg{Type: tea.MouseWheelDown})
+
+	if choice := getChoice(t, m); choice != "b" {
+		t.Errorf("scrolling moved the cursor from b to %q", choice)
+	}
+
+	cmd := test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, Y: 3})
+	if cmd != nil {
+		t.Fatalf("clicking a choice that is not highlighted quit the prompt")
+	}
+
+	// dragging over the highlighted choice does not select it
+	cmd = test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, Y: 3})
+	if cmd != nil {
+		t.Fatalf("dragging the mouse quit the prompt")
+	}
+
+	test.Update(t, m, tea.MouseMsg{Type: tea.MouseRelease, Y: 3})
+
+	cmd = test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, Y: 3})
+	if cmd == nil {
+		t.Fatalf("clicking the highlighted choice did not quit the prompt")
+	}
+
+	if choice := getChoice(t, m); choice != "c" {
+		t.Errorf("unexpected final choice: %q, expected c", choice)
+	}
+}
+
+func TestMouseCustomTemplate(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.EnableMouse = true
+	s.Template = `
+{{- range $i, $choice := .Choices }}
+  {{- if eq $.SelectedIndex $i }}{{ print "> " (Selected $choice) }}{{ else }}{{ print "  " (Unselected $choice) }}{{ end }}
+  {{- print "\n" "  ----\n" }}
+{{- end }}`
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, click(4)...)
+	assertNoError(t, m)
+
+	if choice := getChoice(t, m); choice != "c" {
+		t.Errorf("unexpected choice after click: %q, expected c", choice)
+	}
+
+	// rows that do not belong to a choice are ignored
+	for _, msg := range click(3) {
+		test.Update(t, m, msg)
+	}
+
+	if choice := getChoice(t, m); choice != "c" {
+		t.Errorf("clicking a separator changed the choice to %q", choice)
+	}
+}
+
+func TestMouseMulti(t *testing.T) {
+	t.Parallel()
+
+	s := selection.NewMulti("foo:", []string{"a", "b", "c"})
+	s.EnableMouse = true
+
+	m := selection.NewMultiModel(s)
+
+	test.Run(t, m, append(click(2), click(4)...)...)
+	assertNoError(t, m.Model)
+
+	assertValues(t, m, []string{"a", "c"})
+}
+
+func TestMouseLostRelease(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.EnableMouse = true
+
+	m := selection.NewModel(s)
+
+	// the release event of the first click is lost, but the wheel event
+	// shows that the button is no longer pressed
+	test.Run(t, m,
+		tea.MouseMsg{Type: tea.MouseLeft, Y: 3},
+		tea.MouseMsg{Type: tea.MouseWheelDown},
+		tea.MouseMsg{Type: tea.MouseLeft, Y: 4},
+	)
+	assertNoError(t, m)
+
+	if choice := getChoice(t, m); choice != "c" {
+		t.Errorf("unexpected choice after click: %q, expected c", choice)
+	}
+}
+
+func TestMouseGrid(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c", "d", "e", "f"})
+	s.EnableMouse = true
+	s.Grid = true
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.WindowSizeMsg{Width: 20, Height: 10})
+	assertNoError(t, m)
+
+	// click into the second cell of the first row
+	row := strings.Split(m.View(), "\n")[2]
+	column := ansi.PrintableRuneWidth(row[:strings.Index(row, "b")])
+	test.Update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: column, Y: 2})
+
+	if choice := getChoice(t, m); choice != "b" {
+		t.Errorf("unexpected choice after click: %q, expected b\n%s", choice, m.View())
+	}
+}
+
+func TestMouseChoiceStyle(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c"})
+	s.EnableMouse = true
+	s.UnselectedChoiceStyle = func(c *selection.Choice[string]) string {
+		// the markers that locate clicked choices are not part of the string
+		// representation
+		if c.String != c.Value {
+			t.Errorf("unexpected string representation %q of choice %q", c.String, c.Value)
+		}
+
+		return strings.ToUpper(c.String)
+	}
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, click(4)...)
+	assertNoError(t, m)
+
+	if choice := getChoice(t, m); choice != "c" {
+		t.Errorf("unexpected choice after click: %q, expected c", choice)
+	}
+}
@@ -0,0 +1,142 @@
+package selection
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// DefaultMultiTemplate defines the default appearance of the
+	// multi-selection and can be copied as a starting point for a custom
+	// template.
+	DefaultMultiTemplate = `
+{{- template "header" . }}
+{{- with .TableHeader }}
+  {{- print "      " }}{{ if $.IsQuickSelect }}{{ "  " }}{{ end }}{{ print (Bold .) "\n" }}
+{{- end }}
+
+{{- range  $i, $choice := .Choices }}
+  {{- if IsGroupStart $i }}{{ template "groupHeader" $choice }}{{ end }}
+  {{- template "scrollHint" $i }}
+  {{- if $.IsQuickSelect }}{{ template "quickSelectLabel" $i }}{{ end }}
+
+  {{- if eq $.SelectedIndex $i }}
+    {{- print (Foreground "32" (Bold "▸ ")) -}}
+  {{- else }}
+    {{- print "  " -}}
+  {{- end }}
+
+  {{- if IsChecked $choice }}
+    {{- print (Foreground "32" "◉ ") -}}
+  {{- else }}
+    {{- print "○ " -}}
+  {{- end }}
+
+  {{- if $choice.Disabled }}
+    {{- print (Disabled $choice) }}
+  {{- else if eq $.SelectedIndex $i }}
+    {{- print (Selected $choice) }}
+  {{- else }}
+    {{- print (Unselected $choice) }}
+  {{- end }}
+  {{- template "hotkey" $choice }}
+  {{- "\n" }}
+{{- end}}
+{{- template "footer" . }}
+{{- if .ValidationError }}
+  {{- print "  " (Foreground "1" .ValidationError.Error) "\n" }}
+{{- end }}`
+
+	// DefaultMultiResultTemplate defines the default appearance with which the
+	// final result of the multi-selection is presented.
+	DefaultMultiResultTemplate = `
+	{{- print .Prompt " " -}}
+	{{- range $i, $choice := .FinalChoices }}
+		{{- if $i }}{{ ", " }}{{ end }}{{ Final $choice }}
+	{{- end }}
+	{{- "\n" -}}
+	`
+)
+
+// MultiSelection represents a configurable selection prompt that allows users
+// to select multiple of the pre-defined choices. It builds upon Selection such
+// that filtering, pagination and customization work the same way.
+type MultiSelection[T any] struct {
+	*Selection[T]
+
+	// MinSelected is the minimum number of choices that have to be checked
+	// before the selection can be submitted. If it is 0, no minimum is
+	// enforced.
+	MinSelected int
+
+	// MaxSelected is the maximum number of choices that can be checked when
+	// the selection is submitted. If it is 0, no maximum is enforced.
+	MaxSelected int
+}
+
+// NewMulti creates a new multi-selection prompt. It can be configured in the
+// same way as a regular selection, however, Template and ResultTemplate
+// additionally have access to the following variables and functions:
+//
+//   - IsChecked(*Choice) bool: Whether or not a choice is checked.
+//   - NChecked int: The number of checked choices.
+//   - MinSelected int: The configured minimum number of checked choices.
+//   - MaxSelected int: The configured maximum number of checked choices.
+//   - ValidationError error: Holds the reason why the last attempt to submit
+//     the selection was rejected. It resets when checking or unchecking
+//     choices.
+//
+// If EnableMouse is set, clicking a choice checks or unchecks it rather than
+// submitting the selection.
+//
+// Instead of FinalChoice, the ResultTemplate has access to FinalChoices which
+// holds all checked choices. See the Selection and MultiSelection properties
+// for more documentation.
+func NewMulti[T any](prompt string, choices []T) *MultiSelection[T] {
+	selection := New(prompt, choices)
+	selection.Template = DefaultMultiTemplate
+	selection.ResultTemplate = DefaultMultiResultTemplate
+
+	return &MultiSelection[T]{Selection: selection}
+}
+
+// NewMultiFromChoices creates a new multi-selection prompt from choices that
+// were created with NewChoice such that individual choices can be configured,
+// for example with a hotkey. See NewMulti and NewFromChoices for more
+// documentation.
+func NewMultiFromChoices[T any](prompt string, choices []*Choice[T]) *MultiSelection[T] {
+	selection := NewFromChoices(prompt, choices)
+	selection.Template = DefaultMultiTemplate
+	selection.ResultTemplate = DefaultMultiResultTemplate
+
+	return &MultiSelection[T]{Selection: selection}
+}
+
+// RunPrompt executes the multi-selection prompt.
+func (s *MultiSelection[T]) RunPrompt() ([]T, error) {
+	m := NewMultiModel(s)
+
+	p := tea.NewProgram(m, s.programOptions()...)
+
+	_, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("running prompt: %w", err)
+	}
+
+	s.printResultView(m)
+
+	return m.Values()
+}
+
+func (s *MultiSelection[T]) validateCount(n int) error {
+	if s.MinSelected > 0 && n < s.MinSelected {
+		return fmt.Errorf("select at least %d", s.MinSelected)
+	}
+
+	if s.MaxSelected > 0 && n > s.MaxSelected {
+		return fmt.Errorf("select at most %d", s.MaxSelected)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package selection_test
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func TestMultiToggle(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewMultiModel(selection.NewMulti("foo:", []string{"a", "b", "c"}))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, tea.KeyTab, tea.KeyDown, tea.KeyDown, tea.KeyTab)
+	assertNoError(t, m.Model)
+	test.AssertGoldenView(t, m, "multi_toggle.golden")
+
+	assertValues(t, m, []string{"a", "c"})
+
+	test.Update(t, m, tea.KeyUp)
+	test.Update(t, m, tea.KeyUp)
+	test.Update(t, m, tea.KeyTab)
+
+	assertValues(t, m, []string{"c"})
+
+	test.Update(t, m, tea.KeyEnter)
+	test.AssertGoldenView(t, m, "multi_toggle_confirmed.golden")
+}
+
+func TestMultiSelectAllFiltered(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewMultiModel(selection.NewMulti("foo:", []string{
+		"AAA", "BBB1", "CCC", "BBB2",
+	}))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, append(test.MsgsFromText("BBB"), tea.KeyCtrlA)...)
+	assertNoError(t, m.Model)
+	test.AssertGoldenView(t, m, "multi_select_all.golden")
+
+	assertValues(t, m, []string{"BBB1", "BBB2"})
+}
+
+func TestMultiInvert(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewMultiModel(selection.NewMulti("foo:", []string{"a", "b", "c"}))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, tea.KeyTab, tea.KeyCtrlR)
+	assertNoError(t, m.Model)
+
+	assertValues(t, m, []string{"b", "c"})
+}
+
+func TestMultiMinSelected(t *testing.T) {
+	t.Parallel()
+
+	s := selection.NewMulti("foo:", []string{"a", "b", "c"})
+	s.MinSelected = 2
+	s.ColorProfile = termenv.TrueColor
+	m := selection.NewMultiModel(s)
+
+	test.Run(t, m, tea.KeyTab)
+	assertNoError(t, m.Model)
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd != nil {
+		t.Fatalf("submitting with too few checked choices produced a command")
+	}
+
+	test.AssertGoldenView(t, m, "multi_min_selected.golden")
+
+	test.Update(t, m, tea.KeyDown)
+	test.Update(t, m, tea.KeyTab)
+
+	cmd = test.Update(t, m, tea.KeyEnter)
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("enter did not produce quit signal")
+	}
+}
+
+func TestMultiMaxSelected(t *testing.T) {
+	t.Parallel()
+
+	s := selection.NewMulti("foo:", []string{"a", "b", "c"})
+	s.MaxSelected = 1
+	m := selection.NewMultiModel(s)
+
+	test.Run(t, m, tea.KeyCtrlA)
+	assertNoError(t, m.Model)
+
+	cmd := test.Update(t, m, tea.KeyEnter)
+	if cmd != nil {
+		t.Fatalf("submitting with too many checked choices produced a command")
+	}
+
+	test.Update(t, m, tea.KeyCtrlR)
+	test.Update(t, m, tea.KeyTab)
+
+	cmd = test.Update(t, m, tea.KeyEnter)
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Errorf("enter did not produce quit signal")
+	}
+
+	assertValues(t, m, []string{"a"})
+}
+
+func assertValues[T any](tb testing.TB, m *selection.MultiModel[T], expected []T) {
+	tb.Helper()
+
+	values, err := m.Values()
+	if err != nil {
+		tb.Fatalf("values: %v", err)
+	}
+
+	if !reflect.DeepEqual(values, expected) {
+		tb.Fatalf("unexpected values %v, expected %v", values, expected)
+	}
+}
@@ -0,0 +1,226 @@
+package selection
+
+import (
+	"sort"
+	"text/template"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MultiModel implements the bubbletea.Model for a multi-selection prompt.
+type MultiModel[T any] struct {
+	*Model[T]
+
+	multi *MultiSelection[T]
+
+	// checked choices by their index
+	checked         map[int]*Choice[T]
+	validationError error
+}
+
+// ensure that the Model interface is implemented.
+var _ tea.Model = &MultiModel[any]{}
+
+// NewMultiModel returns a new multi-selection prompt model for the provided
+// choices.
+func NewMultiModel[T any](multiSelection *MultiSelection[T]) *MultiModel[T] {
+	m := &MultiModel[T]{
+		Model:   NewModel(multiSelection.Selection),
+		multi:   multiSelection,
+		checked: map[int]*Choice[T]{},
+	}
+
+	m.Model.rowPrefixWidth = defaultRowPrefixWidth + checkboxWidth
+	m.Model.canCreateChoices = false
+
+	m.Model.extraTemplateFuncs = template.FuncMap{
+		"IsChecked": m.isChecked,
+	}
+	m.Model.choicesChanged = m.updateChecked
+	m.Model.validateKeyMap = func() error {
+		return validateMultiKeyMap(m.Selection, m.hotkeys()...)
+	}
+	m.Model.finalTemplateData = func() (map[string]interface{}, error) {
+		choices, err := m.ValuesAsChoices()
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{"FinalChoices": choices}, nil
+	}
+	m.Model.extraTemplateData = func() map[string]interface{} {
+		return map[string]interface{}{
+			"NChecked":        len(m.checked),
+			"MinSelected":     m.multi.MinSelected,
+			"MaxSelected":     m.multi.MaxSelected,
+			"ValidationError": m.validationError,
+		}
+	}
+
+	return m
+}
+
+// Init initializes the multi-selection prompt model.
+func (m *MultiModel[T]) Init() tea.Cmd {
+	return m.Model.Init()
+}
+
+// ValuesAsChoices returns the checked values wrapped in Choice structs in the
+// order in which they were originally provided.
+func (m *MultiModel[T]) ValuesAsChoices() ([]*Choice[T], error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+
+	choices := make([]*Choice[T], 0, len(m.checked))
+	for _, choice := range m.checked {
+		choices = append(choices, choice)
+	}
+
+	sort.Slice(choices, func(i, j int) bool {
+		return choices[i].idx < choices[j].idx
+	})
+
+	return choices, nil
+}
+
+// Values returns the values that are currently checked or the final values
+// after the prompt has concluded.
+func (m *MultiModel[T]) Values() ([]T, error) {
+	choices, err := m.ValuesAsChoices()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]T, 0, len(choices))
+	for _, choice := range choices {
+		values = append(values, choice.Value)
+	}
+
+	return values, nil
+}
+
+// Update updates the model based on the received message.
+func (m *MultiModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.Err != nil {
+		return m, tea.Quit
+	}
+
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		m.userInteracted()
+	}
+
+	// in contrast to the selection prompt, clicking a choice toggles it
+	mouseMsg, ok := msg.(tea.MouseMsg)
+	if ok && m.EnableMouse && !m.quitting && mouseMsg.Type == tea.MouseLeft {
+		idx, clicked := m.clickedChoiceIndex(mouseMsg)
+		if clicked {
+			m.currentIdx = idx
+			m.toggle(m.currentChoices[idx])
+		}
+
+		return m, m.updatePreview()
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || m.quitting || keyMatches(keyMsg, m.KeyMap.Abort) {
+		_, cmd := m.Model.Update(msg)
+
+		return m, cmd
+	}
+
+	hotkeyChoice := m.hotkeyChoice(keyMsg)
+
+	switch {
+	case keyMatches(keyMsg, m.KeyMap.Select):
+		m.validationError = m.multi.validateCount(len(m.checked))
+		if m.validationError != nil {
+			return m, nil
+		}
+
+		m.quitting = true
+
+		choices, err := m.ValuesAsChoices()
+		if err != nil {
+			return m, tea.Quit
+		}
+
+		return m, m.recordHistory(choices...)
+	case keyMatches(keyMsg, m.KeyMap.Toggle):
+		choice, err := m.ValueAsChoice()
+		if err != nil || choice.Disabled {
+			return m, nil
+		}
+
+		m.toggle(choice)
+	case keyMatches(keyMsg, m.KeyMap.SelectAll):
+		for _, choice := range m.filteredChoices() {
+			if !choice.Disabled {
+				m.check(choice)
+			}
+		}
+	case keyMatches(keyMsg, m.KeyMap.Invert):
+		for _, choice := range m.filteredChoices() {
+			if !choice.Disabled {
+				m.toggle(choice)
+			}
+		}
+	case hotkeyChoice != nil:
+		if m.highlightChoice(hotkeyChoice) {
+			m.toggle(hotkeyChoice)
+		}
+	case m.quickSelectIndex(keyMsg) >= 0:
+		m.currentIdx = m.quickSelectIndex(keyMsg)
+
+		choice := m.currentChoices[m.currentIdx]
+		if !choice.Disabled {
+			m.toggle(choice)
+		}
+	default:
+		_, cmd := m.Model.Update(msg)
+
+		return m, cmd
+	}
+
+	return m, m.updatePreview()
+}
+
+// updateChecked re-associates the checked choices with their indices after the
+// choices were changed and unchecks the choices that were removed.
+func (m *MultiModel[T]) updateChecked() {
+	checked := make(map[*Choice[T]]bool, len(m.checked))
+	for _, choice := range m.checked {
+		checked[choice] = true
+	}
+
+	m.checked = make(map[int]*Choice[T], len(checked))
+
+	for _, choice := range m.allChoices() {
+		if checked[choice] {
+			m.checked[choice.idx] = choice
+		}
+	}
+}
+
+func (m *MultiModel[T]) isChecked(choice *Choice[T]) bool {
+	_, checked := m.checked[choice.idx]
+
+	return checked
+}
+
+func (m *MultiModel[T]) check(choice *Choice[T]) {
+	m.checked[choice.idx] = choice
+	m.validationError = nil
+}
+
+func (m *MultiModel[T]) toggle(choice *Choice[T]) {
+	if m.isChecked(choice) {
+		delete(m.checked, choice.idx)
+		m.validationError = nil
+
+		return
+	}
+
+	m.check(choice)
+}
@@ -0,0 +1,151 @@
+package selection
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// AddChoicesMsg can be sent to a running selection in order to append choices.
+// Like the other messages that change the choices, it is ignored by selections
+// that were created with NewFromSource, which display an error instead until
+// the next key press (see the ChoicesError template variable).
+type AddChoicesMsg[T any] struct {
+	Choices []T
+}
+
+// RemoveChoicesMsg can be sent to a running selection in order to remove all
+// choices for which Remove returns true.
+type RemoveChoicesMsg[T any] struct {
+	Remove func(*Choice[T]) bool
+}
+
+// ReplaceChoicesMsg can be sent to a running selection in order to replace all
+// choices.
+type ReplaceChoicesMsg[T any] struct {
+	Choices []T
+}
+
+// UpdateChoiceMsg can be sent to a running selection in order to change the
+// value of the choice with the given index. The string representation of the
+// choice is updated accordingly.
+type UpdateChoiceMsg[T any] struct {
+	Index int
+	Value T
+}
+
+// updateChoices changes the choices of the selection while keeping the
+// highlighted choice highlighted. If it was removed, the cursor stays at the
+// same position. The choices of custom choice sources cannot be changed, in
+// which case the message is ignored and the problem is displayed instead.
+func (m *Model[T]) updateChoices(mutate func(src *sliceSource[T])) tea.Cmd {
+	src, ok := m.source.(*sliceSource[T])
+	if !ok {
+		m.choicesErr = fmt.Errorf("choices of custom choice sources cannot be changed")
+
+		return nil
+	}
+
+	position := m.currentPosition()
+	highlighted, _ := m.ValueAsChoice()
+
+	mutate(src)
+	src.reindex()
+	m.indexHotkeys(src.choices)
+	m.updateTable()
+	src.invalidateFilterCache()
+
+	if highlighted != nil {
+		highlightedPosition, found := src.position(m.filterText(), highlighted)
+		if found {
+			position = highlightedPosition
+		}
+	}
+
+	if m.Grid {
+		m.updateGridCellWidth(src.choices)
+	}
+
+	m.moveToPosition(position)
+
+	if m.height > 0 {
+		m.forceUpdatePageSizeForHeight()
+	}
+
+	m.ensureSelectable()
+
+	if m.choicesChanged != nil {
+		m.choicesChanged()
+	}
+
+	// the indices of the choices may have changed such that the preview
+	// cannot be associated with the highlighted choice anymore
+	if m.highlightedChoice() != highlighted {
+		m.previewRequested = false
+	}
+
+	return nil
+}
+
+// choicesError returns why the choices of a custom choice source could not be
+// changed or an empty string if they were not changed since the last key
+// press.
+func (m *Model[T]) choicesError() string {
+	if m.choicesErr == nil {
+		return ""
+	}
+
+	return m.choicesErr.Error()
+}
+
+func (m *Model[T]) addChoices(msg AddChoicesMsg[T]) tea.Cmd {
+	return m.updateChoices(func(src *sliceSource[T]) {
+		src.choices = append(src.choices, asChoices(msg.Choices)...)
+	})
+}
+
+func (m *Model[T]) removeChoices(msg RemoveChoicesMsg[T]) tea.Cmd {
+	return m.updateChoices(func(src *sliceSource[T]) {
+		remaining := make([]*Choice[T], 0, len(src.choices))
+
+		for _, choice := range src.choices {
+			if msg.Remove == nil || !msg.Remove(choice) {
+				remaining = append(remaining, choice)
+			}
+		}
+
+		src.choices = remaining
+	})
+}
+
+func (m *Model[T]) replaceChoices(msg ReplaceChoicesMsg[T]) tea.Cmd {
+	return m.updateChoices(func(src *sliceSource[T]) {
+		src.choices = asChoices(msg.Choices)
+	})
+}
+
+func (m *Model[T]) updateChoice(msg UpdateChoiceMsg[T]) tea.Cmd {
+	var updated *Choice[T]
+
+	cmd := m.updateChoices(func(src *sliceSource[T]) {
+		if msg.Index < 0 || msg.Index >= len(src.choices) {
+			return
+		}
+
+		updated = src.choices[msg.Index]
+
+		value := newChoice(msg.Value)
+		updated.Value = value.Value
+		updated.String = value.String
+		updated.tableCells = nil
+	})
+
+	// the preview of the highlighted choice is outdated if it was updated
+	if updated != nil && updated == m.highlightedChoice() {
+		m.previewRequested = false
+
+		return m.updatePreview()
+	}
+
+	return cmd
+}
@@ -0,0 +1,121 @@
+package selection_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func TestMutateChoices(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a", "b", "c", "d", "e"})
+	s.PageSize = 3
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, tea.KeyDown, tea.KeyDown, tea.KeyDown)
+	assertNoError(t, m)
+
+	test.Update(t, m, selection.RemoveChoicesMsg[string]{
+		Remove: func(c *selection.Choice[string]) bool { return c.Value == "b" },
+	})
+	test.AssertGoldenView(t, m, "mutation_removed.golden")
+
+	if choice := getChoice(t, m); choice != "d" {
+		t.Errorf("removing a choice moved the cursor to %q", choice)
+	}
+
+	test.Update(t, m, selection.AddChoicesMsg[string]{Choices: []string{"f", "g"}})
+
+	if choice := getChoice(t, m); choice != "d" {
+		t.Errorf("adding choices moved the cursor to %q", choice)
+	}
+
+	test.Update(t, m, selection.UpdateChoiceMsg[string]{Index: 2, Value: "D"})
+	test.AssertGoldenView(t, m, "mutation_updated.golden")
+
+	if choice := getChoice(t, m); choice != "D" {
+		t.Errorf("unexpected choice after update: %q, expected D", choice)
+	}
+
+	test.Update(t, m, selection.ReplaceChoicesMsg[string]{Choices: []string{"x", "y"}})
+
+	if choice := getChoice(t, m); choice != "y" {
+		t.Errorf("unexpected choice after replacing the choices: %q, expected y", choice)
+	}
+}
+
+func TestMutateChoicesFiltered(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewModel(selection.New("foo:", []string{"apple", "apricot", "banana"}))
+
+	test.Run(t, m, append(test.MsgsFromText("ap"), tea.KeyDown)...)
+	assertNoError(t, m)
+
+	test.Update(t, m, selection.RemoveChoicesMsg[string]{
+		Remove: func(c *selection.Choice[string]) bool { return c.Value == "apple" },
+	})
+
+	if choice := getChoice(t, m); choice != "apricot" {
+		t.Errorf("removing a choice moved the cursor to %q", choice)
+	}
+
+	test.Update(t, m, selection.AddChoicesMsg[string]{Choices: []string{"grape"}})
+
+	if choice := getChoice(t, m); choice != "apricot" {
+		t.Errorf("adding a choice that does not match the filter moved the cursor to %q", choice)
+	}
+}
+
+func TestMutateChoicesCustomSource(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewModel(selection.NewFromSource[int]("foo:", &numberSource{n: 10}))
+
+	test.Run(t, m)
+
+	cmd := test.Update(t, m, selection.AddChoicesMsg[int]{Choices: []int{10}})
+	if cmd != nil {
+		t.Errorf("changing the choices of a custom source produced a command")
+	}
+
+	assertNoError(t, m)
+
+	if !strings.Contains(m.View(), "choices of custom choice sources cannot be changed") {
+		t.Errorf("view does not display why the choices were not changed:\n%s", m.View())
+	}
+
+	test.Update(t, m, tea.KeyDown)
+
+	if strings.Contains(m.View(), "choices of custom choice sources cannot be changed") {
+		t.Errorf("view still displays why the choices were not changed after a key press:\n%s", m.View())
+	}
+}
+
+func TestMutateChoicesMulti(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewMultiModel(selection.NewMulti("foo:", []string{"a", "b", "c", "d"}))
+
+	test.Run(t, m, tea.KeyDown, tea.KeyTab, tea.KeyDown, tea.KeyDown, tea.KeyTab)
+	assertNoError(t, m.Model)
+	assertValues(t, m, []string{"b", "d"})
+
+	test.Update(t, m, selection.RemoveChoicesMsg[string]{
+		Remove: func(c *selection.Choice[string]) bool { return c.Value == "a" || c.Value == "b" },
+	})
+	assertValues(t, m, []string{"d"})
+
+	test.Update(t, m, selection.AddChoicesMsg[string]{Choices: []string{"e"}})
+	assertValues(t, m, []string{"d"})
+
+	test.Update(t, m, selection.ReplaceChoicesMsg[string]{Choices: []string{"d", "f"}})
+	assertValues(t, m, []string{})
+}
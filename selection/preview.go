@@ -0,0 +1,180 @@
+package selection
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// PreviewPosition determines where the preview of the highlighted choice is
+// displayed relative to the choices.
+type PreviewPosition int
+
+const (
+	// PreviewBelow displays the preview below the choices.
+	PreviewBelow PreviewPosition = iota
+	// PreviewRight displays the preview to the right of the choices, each
+	// taking up half of the terminal width.
+	PreviewRight
+)
+
+// DefaultPreviewHeight is the default number of lines that are reserved for a
+// preview that is displayed below the choices.
+const DefaultPreviewHeight = 10
+
+// previewLoadingText is displayed until the first preview is available.
+const previewLoadingText = "Loading preview..."
+
+// previewDebounce is the pause in cursor movement after which the preview of
+// the highlighted choice is computed, such that no previews are computed for
+// choices that are only passed while the cursor moves.
+const previewDebounce = 100 * time.Millisecond
+
+// previewDebounceMsg triggers the computation of the preview that was
+// requested with the sequence number seq.
+type previewDebounceMsg struct {
+	seq int
+}
+
+// previewMsg transports the asynchronously computed preview that was
+// requested with the sequence number seq to the model.
+type previewMsg struct {
+	seq     int
+	content string
+}
+
+// updatePreview returns a command that requests the preview of the highlighted
+// choice in case the highlighted choice changed since the last preview was
+// requested. Apart from the initial preview, the computation is debounced.
+// The previous preview stays visible until the new one is available to avoid
+// flickering.
+func (m *Model[T]) updatePreview() tea.Cmd {
+	if m.Preview == nil || m.quitting {
+		return nil
+	}
+
+	// the entry to create a new choice has no value to preview yet
+	choice, err := m.ValueAsChoice()
+	if err != nil || choice.create {
+		m.previewRequested = false
+		m.preview = ""
+
+		return nil
+	}
+
+	if m.previewRequested && choice.idx == m.previewIdx {
+		return nil
+	}
+
+	initial := !m.previewAvailable && !m.previewRequested
+
+	m.previewRequested = true
+	m.previewIdx = choice.idx
+	m.previewSeq++
+
+	if initial {
+		return m.computePreview(previewDebounceMsg{seq: m.previewSeq})
+	}
+
+	seq := m.previewSeq
+
+	return tea.Tick(previewDebounce, func(time.Time) tea.Msg {
+		return previewDebounceMsg{seq: seq}
+	})
+}
+
+// computePreview returns a command that computes the preview of the
+// highlighted choice unless another preview was requested in the meantime.
+func (m *Model[T]) computePreview(msg previewDebounceMsg) tea.Cmd {
+	if !m.previewRequested || msg.seq != m.previewSeq {
+		return nil
+	}
+
+	choice, err := m.ValueAsChoice()
+	if err != nil || choice.create {
+		return nil
+	}
+
+	// the model keeps changing the highlighted choice, for example when it is
+	// updated with UpdateChoiceMsg, while the preview is computed
+	previewed := *choice
+	preview := m.Preview
+
+	return func() tea.Msg {
+		return previewMsg{seq: msg.seq, content: preview(&previewed)}
+	}
+}
+
+// receivePreview stores the preview unless another preview was requested in
+// the meantime.
+func (m *Model[T]) receivePreview(msg previewMsg) {
+	if !m.previewRequested || msg.seq != m.previewSeq {
+		return
+	}
+
+	m.preview = msg.content
+	m.previewAvailable = true
+}
+
+// withPreview combines the view of the choices with the preview of the
+// highlighted choice according to the PreviewPosition.
+func (m *Model[T]) withPreview(view string) string {
+	preview := m.preview
+	if !m.previewAvailable && m.previewRequested {
+		preview = termenv.String(previewLoadingText).Faint().String()
+	}
+
+	view = strings.TrimSuffix(view, "\n")
+
+	if m.PreviewPosition == PreviewRight {
+		previewWidth := m.width / 2 //nolint:gomnd
+		listWidth := m.width - previewWidth
+
+		// leave one column of space between the choices and the preview
+		list := m.wrapWidth(view, listWidth-1)
+		listHeight := lipgloss.Height(list)
+
+		previewStyle := lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			PaddingLeft(1).
+			Height(listHeight)
+
+		listStyle := lipgloss.NewStyle()
+
+		if m.width > 0 {
+			listStyle = listStyle.Width(listWidth).PaddingRight(1)
+			previewStyle = previewStyle.MaxWidth(previewWidth)
+		}
+
+		return lipgloss.JoinHorizontal(lipgloss.Top,
+			listStyle.Render(list), previewStyle.Render(firstLines(preview, listHeight))) + "\n"
+	}
+
+	height := m.PreviewHeight
+	if height <= 0 {
+		height = DefaultPreviewHeight
+	}
+
+	previewStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), true, false, false, false).
+		Height(height)
+
+	if m.width > 0 {
+		previewStyle = previewStyle.MaxWidth(m.width)
+	}
+
+	return m.wrap(view) + "\n" + previewStyle.Render(firstLines(preview, height)) + "\n"
+}
+
+// firstLines returns at most n lines of the text.
+func firstLines(text string, n int) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,137 @@
+package selection_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func previewFile(c *selection.Choice[string]) string {
+	return "contents of " + c.Value + "\nline 2\nline 3"
+}
+
+func TestPreviewBelow(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a.txt", "b.txt", "c.txt"})
+	s.Preview = previewFile
+	s.PreviewHeight = 2
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	runCmd(t, m, m.Init())
+	assertNoError(t, m)
+	runCmd(t, m, test.Update(t, m, tea.KeyDown))
+
+	test.AssertGoldenView(t, m, "preview_below.golden")
+}
+
+func TestPreviewRight(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a.txt", "b.txt", "c.txt"})
+	s.Preview = previewFile
+	s.PreviewPosition = selection.PreviewRight
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	runCmd(t, m, m.Init())
+	assertNoError(t, m)
+	runCmd(t, m, test.Update(t, m, tea.WindowSizeMsg{Width: 60, Height: 20}))
+
+	test.AssertGoldenView(t, m, "preview_right.golden")
+}
+
+func TestPreviewIgnoresOutdatedResults(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a.txt", "b.txt", "c.txt"})
+	s.Preview = previewFile
+
+	m := selection.NewModel(s)
+
+	// the preview is still loading until the command was executed
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if !strings.Contains(m.View(), "Loading preview...") {
+		t.Errorf("view does not indicate that the preview is loading:\n%s", m.View())
+	}
+
+	slowPreview := test.Update(t, m, tea.KeyDown)
+	fastPreview := test.Update(t, m, tea.KeyDown)
+
+	runCmd(t, m, fastPreview)
+	runCmd(t, m, slowPreview)
+
+	if !strings.Contains(m.View(), "contents of c.txt") {
+		t.Errorf("view does not contain the preview of the highlighted choice:\n%s", m.View())
+	}
+}
+
+func TestPreviewUpdatedChoice(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"a.txt", "b.txt"})
+	s.Preview = previewFile
+
+	m := selection.NewModel(s)
+
+	runCmd(t, m, m.Init())
+	assertNoError(t, m)
+	runCmd(t, m, test.Update(t, m, selection.UpdateChoiceMsg[string]{Index: 0, Value: "z.txt"}))
+
+	if !strings.Contains(m.View(), "contents of z.txt") {
+		t.Errorf("view does not contain the preview of the updated choice:\n%s", m.View())
+	}
+}
+
+func TestPreviewOfChoiceCopy(t *testing.T) {
+	t.Parallel()
+
+	var previewed []string
+
+	s := selection.New("foo:", []string{"a.txt", "b.txt"})
+	s.Preview = func(c *selection.Choice[string]) string {
+		previewed = append(previewed, c.Value)
+
+		return previewFile(c)
+	}
+
+	m := selection.NewModel(s)
+
+	// the choice is updated before the requested preview is computed
+	cmd := m.Init()
+	assertNoError(t, m)
+	test.Update(t, m, selection.UpdateChoiceMsg[string]{Index: 0, Value: "z.txt"})
+	runCmd(t, m, cmd)
+
+	if len(previewed) != 1 || previewed[0] != "a.txt" {
+		t.Errorf("unexpected previewed choices: %v, expected the choice as it was requested", previewed)
+	}
+}
+
+// runCmd executes the command as well as all batched commands and applies the
+// resulting messages to the model.
+func runCmd(tb testing.TB, m tea.Model, cmd tea.Cmd) {
+	tb.Helper()
+
+	if cmd == nil {
+		return
+	}
+
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			runCmd(tb, m, cmd)
+		}
+	default:
+		runCmd(tb, m, test.Update(tb, m, msg))
+	}
+}
@@ -22,28 +22,27 @@ const (
 	// DefaultTemplate defines the default appearance of the selection and can
 	// be copied as a starting point for a custom template.
 	DefaultTemplate = `
-{{- if .Prompt -}}
-  {{ Bold .Prompt }}
-{{ end -}}
-{{ if .IsFiltered }}
-  {{- print .FilterPrompt " " .FilterInput }}
-{{ end }}
+{{- template "header" . }}
+{{- with .TableHeader }}
+  {{- print "    " }}{{ if $.IsQuickSelect }}{{ "  " }}{{ end }}{{ print (Bold .) "\n" }}
+{{- end }}
 
 {{- range  $i, $choice := .Choices }}
-  {{- if IsScrollUpHintPosition $i }}
-    {{- "⇡ " -}}
-  {{- else if IsScrollDownHintPosition $i -}}
-    {{- "⇣ " -}}
-  {{- else -}}
-    {{- "  " -}}
-  {{- end -}}
-
-  {{- if eq $.SelectedIndex $i }}
-   {{- print (Foreground "32" (Bold "▸ ")) (Selected $choice) "\n" }}
+  {{- if IsGroupStart $i }}{{ template "groupHeader" $choice }}{{ end }}
+  {{- template "scrollHint" $i }}
+  {{- if $.IsQuickSelect }}{{ template "quickSelectLabel" $i }}{{ end }}
+
+  {{- if $choice.Disabled }}
+    {{- print "  " (Disabled $choice) }}
+  {{- else if eq $.SelectedIndex $i }}
+   {{- print (Foreground "32" (Bold "▸ ")) (Selected $choice) }}
   {{- else }}
-    {{- print "  " (Unselected $choice) "\n" }}
+    {{- print "  " (Unselected $choice) }}
   {{- end }}
-{{- end}}`
+  {{- template "hotkey" $choice }}
+  {{- "\n" }}
+{{- end}}
+{{- template "footer" . }}`
 
 	// DefaultResultTemplate defines the default appearance with which the
 	// finale result of the selection is presented.
@@ -62,9 +61,93 @@ const (
 	accentColor = termenv.ANSI256Color(32)
 )
 
-// DefaultSelectedChoiceStyle is the default style for selected choices.
+// templateBlocks are available in all templates of the selection prompts such
+// that the default templates can share them (see Selection.Template).
+const templateBlocks = `
+{{- define "header" }}
+{{- if .Prompt -}}
+  {{ Bold .Prompt }}
+{{ end -}}
+{{ if .IsFiltered }}
+  {{- print .FilterPrompt " " }}
+  {{- if .IsRegexFilter }}{{ print (Faint "[regex]") " " }}{{ end }}
+  {{- .FilterInput }}
+{{ end }}
+{{- with .FilterError }}
+  {{- print "  " (Foreground "1" .) "\n" }}
+{{- end }}
+{{- with .ChoicesError }}
+  {{- print "  " (Foreground "1" .) "\n" }}
+{{- end }}
+{{- end }}
+
+{{- define "groupHeader" }}
+  {{- with .Group }}
+    {{- print "  " (Bold .) "\n" }}
+  {{- else }}
+    {{- "\n" }}
+  {{- end }}
+{{- end }}
+
+{{- define "scrollHint" }}
+  {{- if IsScrollUpHintPosition . }}
+    {{- "⇡ " -}}
+  {{- else if IsScrollDownHintPosition . -}}
+    {{- "⇣ " -}}
+  {{- else -}}
+    {{- "  " -}}
+  {{- end -}}
+{{- end }}
+
+{{- define "quickSelectLabel" }}
+  {{- with QuickSelectLabel . }}
+    {{- print (Faint .) " " }}
+  {{- else }}
+    {{- "  " }}
+  {{- end }}
+{{- end }}
+
+{{- define "hotkey" }}
+  {{- with .Hotkey }}
+    {{- print " " (Faint (print "[" . "]")) }}
+  {{- end }}
+{{- end }}
+
+{{- define "footer" }}
+  {{- if .Loading }}
+    {{- print "  " (Faint "Loading choices...") "\n" }}
+  {{- end }}
+{{- end }}`
+
+// DefaultSelectedChoiceStyle is the default style for selected choices. Runes
+// that were matched by the filter are underlined if match positions are
+// available (see Selection.MatchPositions).
 func DefaultSelectedChoiceStyle[T any](c *Choice[T]) string {
-	return termenv.String(c.String).Foreground(accentColor).Bold().String()
+	return HighlightMatches(c, func(s string) string {
+		return termenv.String(s).Foreground(accentColor).Bold().String()
+	}, func(s string) string {
+		return termenv.String(s).Foreground(accentColor).Bold().Underline().String()
+	})
+}
+
+// DefaultUnselectedChoiceStyle is the default style for unselected choices. It
+// renders the plain string representation of the choice, however, runes that
+// were matched by the filter are underlined if match positions are available
+// (see Selection.MatchPositions).
+func DefaultUnselectedChoiceStyle[T any](c *Choice[T]) string {
+	return HighlightMatches(c, nil, func(s string) string {
+		return termenv.String(s).Underline().String()
+	})
+}
+
+// DefaultDisabledChoiceStyle is the default style for disabled choices. The
+// choice is dimmed and followed by the reason why it is disabled, if any.
+func DefaultDisabledChoiceStyle[T any](c *Choice[T]) string {
+	if c.DisabledReason == "" {
+		return termenv.String(c.String).Faint().String()
+	}
+
+	return termenv.String(c.String + " (" + c.DisabledReason + ")").Faint().String()
 }
 
 // DefaultFinalChoiceStyle is the default style for final choices.
@@ -74,24 +157,71 @@ func DefaultFinalChoiceStyle[T any](c *Choice[T]) string {
 
 // Selection represents a configurable selection prompt.
 type Selection[T any] struct {
-	// choices represent all selectable choices of the selection. Slices of
-	// arbitrary types can be converted to a slice of choices using the helper
-	// selection.choices.
-	choices []*Choice[T]
+	// source provides all selectable choices of the selection. Slices of
+	// arbitrary types are wrapped in a sliceSource by New.
+	source ChoiceSource[T]
 
 	// Prompt holds the prompt text or question that is to be answered by one of
 	// the choices.
 	Prompt string
 
+	// ChoiceStream can be used to provide choices while the selection prompt is
+	// already running, for example when choices are discovered by a slow
+	// directory walk or fetched from a paginated API. Streamed choices are
+	// appended to the choices that were passed to New, which may be empty in
+	// this case. The selection indicates that more choices are expected until
+	// the channel is closed. Choice streams are not supported for selections
+	// created with NewFromSource. The prompt keeps waiting for the next choice
+	// in the background until the channel is closed, even after it concluded.
+	// Once RunPrompt returns, the channel therefore has to be closed or
+	// drained until the producer closes it. Otherwise, the goroutine that
+	// receives the choices as well as producers that block on sending leak.
+	ChoiceStream <-chan T
+
 	// FilterPrompt is the prompt for the filter if filtering is enabled.
 	FilterPrompt string
 
 	// Filter is a function that decides whether a given choice should be
 	// displayed based on the text entered by the user into the filter input
-	// field. If Filter is nil, filtering will be disabled. By default the
-	// filter FilterContainsCaseInsensitive is used.
+	// field. If Filter is nil, filtering will be disabled and typing jumps to
+	// the next choice that starts with the typed text instead. By default the
+	// filter FilterContainsCaseInsensitive is used. FilterQuery provides a
+	// filter with an extended search syntax. While the filter input is
+	// focused, printable keys are typed into it even if they are the Hotkey of
+	// a choice.
 	Filter func(filterText string, choice *Choice[T]) bool
 
+	// ScoreFilter is an alternative to Filter that additionally ranks the
+	// choices that match the filter text. Matching choices are displayed in
+	// descending order of their score, choices with equal scores keep their
+	// original order. As with Filter, the highlighted choice stays
+	// highlighted as long as it matches the filter text, even if it is no
+	// longer ranked first. If ScoreFilter is set, it is used instead of
+	// Filter. The scoring filters ScoreContainsCaseInsensitive and ScoreFuzzy
+	// are provided by this package. Custom choice sources are responsible for
+	// ranking the choices themselves.
+	ScoreFilter func(filterText string, choice *Choice[T]) (score int, ok bool)
+
+	// MatchPositions is a function that determines the positions of the runes
+	// in the string representation of a choice that were matched by the text
+	// entered into the filter input field. The positions are determined for
+	// the displayed choices and are made available through
+	// Choice.MatchPositions such that the choice styles and custom templates
+	// can highlight them. By default, MatchPositions is nil and no positions
+	// are determined. FuzzyMatchPositions complements the FilterFuzzy filter.
+	MatchPositions func(filterText string, choice *Choice[T]) []int
+
+	// RegexFilter determines whether the text entered into the filter input
+	// field is initially interpreted as regular expression instead of being
+	// passed to Filter or ScoreFilter. Regular expressions match the string
+	// representation of the choices without regard for capitalization unless
+	// the (?-i) flag is used. The mode can be toggled with the
+	// ToggleRegexFilter key. While the filter text is not a valid regular
+	// expression, the error is displayed and the last valid one stays in
+	// effect. Regex mode is not supported with NewFromSource, as the choice
+	// source is responsible for filtering in this case.
+	RegexFilter bool
+
 	// FilterPlaceholder holds the text that is displayed in the filter input
 	// field when no text was entered by the user yet. If empty, the
 	// DefaultFilterPlaceholder is used. If Filter is nil, filtering is disabled
@@ -102,12 +232,114 @@ type Selection[T any] struct {
 	// is smaller than the number of choices, pagination is enabled. If PageSize
 	// is 0, pagenation is disabled. Regardless of the value of PageSize,
 	// pagination is always enabled when the prompt does not fit the terminal.
+	// For selections created with NewFromSource, pagination is also enabled
+	// with a page size of 100 as long as the terminal size is unknown.
 	PageSize int
 
 	// LoopCursor enables the cursor to loop around to the first choice when
 	// navigating down from the last choice and the other way around.
 	LoopCursor bool
 
+	// InitialIndex is the index of the choice that is highlighted when the
+	// prompt opens. The choices are scrolled such that it is visible. If the
+	// choices are reordered according to the SortColumn or the History, a
+	// non-zero InitialIndex refers to the order in which they were provided,
+	// whereas the first choice in the new order is highlighted by default.
+	InitialIndex int
+
+	// InitialChoice determines the choice that is highlighted when the prompt
+	// opens by its value. It takes precedence over InitialIndex and the first
+	// choice is highlighted if no choice satisfies it. With a ChoiceStream,
+	// the streamed choices are evaluated as well until one satisfies it or
+	// the user interacts with the prompt.
+	InitialChoice func(T) bool
+
+	// Grid displays the choices in multiple columns which are sized according
+	// to the terminal width and the longest choice. With NewFromSource, only
+	// the first choices of the source are measured. The Left and Right keys
+	// move the cursor between the columns and PageSize specifies the number of
+	// rows instead of the number of choices. Each group of choices starts in a
+	// new row below its header. If the Template is the DefaultTemplate, the
+	// DefaultGridTemplate is used instead.
+	Grid bool
+
+	// Columns displays the choices as table with the given columns. The
+	// columns are aligned across all choices and the widest columns are
+	// truncated such that the rows fit the terminal width. The string
+	// representation of each choice is replaced by its row such that filtering
+	// and the choice styles apply to the entire row as it is displayed. The
+	// default templates display a header row above the choices. Table mode is
+	// not supported with NewFromSource and it cannot be combined with Grid.
+	Columns []Column[T]
+
+	// SortColumn optionally names the header of the column by which the
+	// choices are sorted in table mode. SortDescending reverses the order.
+	// The SortColumn takes precedence over the History, which only orders
+	// choices with equal values in the column.
+	SortColumn     string
+	SortDescending bool
+
+	// CreateChoice optionally offers an entry to create a new choice from the
+	// filter text, such as Create "text", after the matching choices. When the
+	// entry is selected, CreateChoice constructs the value that is returned by
+	// the prompt from the filter text. If it returns an error, the error is
+	// displayed and the prompt continues. CreateChoiceMode determines whether
+	// the entry is offered only if no choice matches the filter text, which is
+	// the default, or always. Creating choices is not supported by the
+	// multi-selection and tree selection prompts.
+	CreateChoice     func(filterText string) (T, error)
+	CreateChoiceMode CreateChoiceMode
+
+	// QuickSelect labels the displayed choices with 1-9 and a-z such that
+	// they can be selected immediately by pressing their label. In the
+	// multi-selection prompt, pressing a label toggles the choice instead. If
+	// filtering is enabled, the filter input has to be focused with the
+	// FocusFilter key before a filter text can be entered and the ClearFilter
+	// key returns to selecting choices by their labels.
+	QuickSelect bool
+
+	// History optionally records the choices that are picked and orders the
+	// choices by frecency, a combination of how frequently and how recently
+	// they were picked, the next time the prompt is run. The order of the
+	// choices is changed when the prompt is initialized, which also affects
+	// their indices. Choices that are added later, such as streamed choices,
+	// as well as the choices of selections created with NewFromSource and
+	// tree selections are not reordered. The picks are recorded in the
+	// background before the prompt concludes. If the history cannot be
+	// persisted, the picks are not recorded but the prompt does not fail.
+	History *History
+
+	// EnableMouse enables choosing choices with the mouse. Clicking a choice
+	// highlights it, clicking the highlighted choice selects it and the mouse
+	// wheel scrolls the choices. In multi-selection prompts, clicking a choice
+	// checks or unchecks it instead and the selection is only submitted with
+	// the Select key. Clicked choices are located by the output of the
+	// Selected, Unselected and Disabled template functions, which custom
+	// templates therefore have to use to render the choices of the Choices
+	// template variable. As the rows of the mouse events have to correspond
+	// to the rows of the view, RunPrompt displays the prompt on the alternate
+	// screen in this case. When the model is embedded in another application,
+	// the mouse events have to be translated accordingly.
+	EnableMouse bool
+
+	// Preview optionally renders a preview of the highlighted choice, such as
+	// the content of a file, which is displayed alongside the choices. The
+	// preview is computed asynchronously once the cursor rested on another
+	// choice for a moment, such that slow previews do not block the prompt
+	// and outdated previews are discarded. As the prompt continues while the
+	// preview is computed, Preview receives a copy of the choice.
+	Preview func(*Choice[T]) string
+
+	// PreviewPosition determines whether the preview is displayed below the
+	// choices, which is the default, or to the right of them.
+	PreviewPosition PreviewPosition
+
+	// PreviewHeight is the number of lines that are reserved for the preview
+	// when it is displayed below the choices. By default DefaultPreviewHeight
+	// is used. A preview to the right of the choices is limited to the height
+	// of the choices instead.
+	PreviewHeight int
+
 	// Template holds the display template. A custom template can be used to
 	// completely customize the appearance of the selection prompt. If empty,
 	// the DefaultTemplate is used. The following variables and functions are
@@ -117,20 +349,57 @@ type Selection[T any] struct {
 	//  * IsFiltered bool: Whether or not filtering is enabled.
 	//  * FilterPrompt string: The configured filter prompt.
 	//  * FilterInput string: The view of the filter input model.
-	//  * Choices []*Choice: The choices on the current page.
+	//  * IsRegexFilter bool: Whether the filter text is interpreted as
+	//    regular expression (see RegexFilter).
+	//  * FilterError string: Why the filter text is not a valid regular
+	//    expression in regex mode or why no choice could be created from it
+	//    (see CreateChoice) or an empty string if there is no such error.
+	//  * ChoicesError string: Why the choices of a custom choice source could
+	//    not be changed (see AddChoicesMsg) until the next key press or an
+	//    empty string.
+	//  * Choices []*Choice: The choices on the current page. The positions of
+	//    runes that were matched by the filter are available through the
+	//    MatchPositions method of each choice (see Selection.MatchPositions).
 	//  * NChoices int: The number of choices on the current page.
 	//  * SelectedIndex int: The index that is currently selected.
 	//  * PageSize int: The configured page size.
 	//  * IsPaged bool: Whether pagination is currently active.
-	//  * AllChoices []*Choice: All configured choices.
+	//  * AllChoices []*Choice: All configured choices (nil for selections
+	//    created with NewFromSource).
 	//  * NAllChoices int: The number of configured choices.
 	//  * TerminalWidth int: The width of the terminal.
+	//  * Loading bool: Whether more choices are expected from the
+	//    ChoiceStream.
+	//  * IsQuickSelect bool: Whether choices can currently be selected by
+	//    pressing their label (see QuickSelect).
 	//  * Selected(*Choice) string: The configured SelectedChoiceStyle.
 	//  * Unselected(*Choice) string: The configured UnselectedChoiceStyle.
+	//  * Disabled(*Choice) string: The configured DisabledChoiceStyle.
 	//  * IsScrollDownHintPosition(idx int) bool: Returns whether
 	//    the scroll down hint should be displayed at the given index.
 	//  * IsScrollUpHintPosition(idx int) bool: Returns whether the
 	//    scroll up hint should be displayed at the given index).
+	//  * IsCreateChoice(*Choice) bool: Returns whether the choice is the entry
+	//    to create a new choice from the filter text (see CreateChoice).
+	//  * IsGroupStart(idx int) bool: Returns whether the choice at the given
+	//    index is the first displayed choice of its group such that the
+	//    group header should be displayed before it. This is also the case
+	//    for the first ungrouped choice after a group, which starts an
+	//    unlabeled section.
+	//  * QuickSelectLabel(idx int) string: Returns the quick-select label of
+	//    the choice at the given index or an empty string if it has none.
+	//  * GridColumns int: The number of columns in grid mode (1 otherwise).
+	//  * GridCellWidth int: The width of the cells in grid mode.
+	//  * IsRowStart(idx int) bool: Returns whether the choice at the given
+	//    index is the first one in its row in grid mode.
+	//  * IsRowEnd(idx int) bool: Returns whether the choice at the given index
+	//    is the last one in its row in grid mode.
+	//  * TableHeader string: The header row in table mode (see Columns) or an
+	//    empty string otherwise.
+	//  * The blocks that the default templates are composed of, which can be
+	//    used with the template action: "header" and "footer" for the data of
+	//    the template, "scrollHint" and "quickSelectLabel" for the index of a
+	//    choice as well as "groupHeader" and "hotkey" for a choice.
 	//  * promptkit.UtilFuncMap: Handy helper functions.
 	//  * termenv TemplateFuncs (see https://github.com/muesli/termenv).
 	//  * The functions specified in ExtendedTemplateFuncs.
@@ -144,7 +413,8 @@ type Selection[T any] struct {
 	//
 	//  * FinalChoice: The choice that was selected by the user.
 	//  * Prompt string: The configured prompt.
-	//  * AllChoices []*Choice: All configured choices.
+	//  * AllChoices []*Choice: All configured choices (nil for selections
+	//    created with NewFromSource).
 	//  * NAllChoices int: The number of configured choices.
 	//  * TerminalWidth int: The width of the terminal.
 	//  * Final(*Choice) string: The configured FinalChoiceStyle.
@@ -174,12 +444,20 @@ type Selection[T any] struct {
 	SelectedChoiceStyle func(*Choice[T]) string
 
 	// UnselectedChoiceStyle style allows to customize the appearance of the
-	// currently unselected choice. By default it is nil, such that no style
-	// will be applied and the plain string representation of the choice will be
-	// used. This style will be available as the template function Unselected.
-	// Custom templates may or may not use this function.
+	// currently unselected choice. By default DefaultUnselectedChoiceStyle is
+	// used. If it is nil, no style will be applied and the plain string
+	// representation of the choice will be used. This style will be available
+	// as the template function Unselected. Custom templates may or may not use
+	// this function.
 	UnselectedChoiceStyle func(*Choice[T]) string
 
+	// DisabledChoiceStyle style allows to customize the appearance of disabled
+	// choices. By default DefaultDisabledChoiceStyle is used. If it is nil, no
+	// style will be applied and the plain string representation of the choice
+	// will be used. This style will be available as the template function
+	// Disabled. Custom templates may or may not use this function.
+	DisabledChoiceStyle func(*Choice[T]) string
+
 	// FinalChoiceStyle style allows to customize the appearance of the choice
 	// that was ultimately chosen. By default DefaultFinalChoiceStyle is used.
 	// If it is nil, no style will be applied and the plain string
@@ -211,8 +489,28 @@ type Selection[T any] struct {
 // New creates a new selection prompt. See the Selection properties for more
 // documentation.
 func New[T any](prompt string, choices []T) *Selection[T] {
+	return NewFromSource[T](prompt, newSliceSource(choices))
+}
+
+// NewFromChoices creates a new selection prompt from choices that were created
+// with NewChoice. This allows configuring individual choices, for example to
+// assign them to a group. The indices of the choices are re-assigned according
+// to their position in the slice. See the Selection properties for more
+// documentation.
+func NewFromChoices[T any](prompt string, choices []*Choice[T]) *Selection[T] {
+	return NewFromSource[T](prompt, newSliceSourceFromChoices(choices))
+}
+
+// NewFromSource creates a new selection prompt with choices that are provided
+// by a custom ChoiceSource. Only the choices that are displayed are requested
+// from the source such that huge or lazily loaded choice sets can be browsed.
+// The source is responsible for filtering the choices in ChoiceSource.Query,
+// such that Filter only determines whether filtering is enabled. The template
+// variable AllChoices is not available for custom sources. See the Selection
+// properties for more documentation.
+func NewFromSource[T any](prompt string, source ChoiceSource[T]) *Selection[T] {
 	return &Selection[T]{
-		choices:                     asChoices(choices),
+		source:                      source,
 		Prompt:                      prompt,
 		FilterPrompt:                DefaultFilterPrompt,
 		Template:                    DefaultTemplate,
@@ -220,9 +518,12 @@ func New[T any](prompt string, choices []T) *Selection[T] {
 		Filter:                      FilterContainsCaseInsensitive[T],
 		FilterInputPlaceholderStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
 		SelectedChoiceStyle:         DefaultSelectedChoiceStyle[T],
+		UnselectedChoiceStyle:       DefaultUnselectedChoiceStyle[T],
+		DisabledChoiceStyle:         DefaultDisabledChoiceStyle[T],
 		FinalChoiceStyle:            DefaultFinalChoiceStyle[T],
 		KeyMap:                      NewDefaultKeyMap(),
 		FilterPlaceholder:           DefaultFilterPlaceholder,
+		PreviewHeight:               DefaultPreviewHeight,
 		ExtendedTemplateFuncs:       template.FuncMap{},
 		WrapMode:                    promptkit.Truncate,
 		Output:                      os.Stdout,
@@ -234,23 +535,41 @@ func New[T any](prompt string, choices []T) *Selection[T] {
 func (s *Selection[T]) RunPrompt() (T, error) {
 	var zeroValue T
 
-	err := validateKeyMap(s.KeyMap)
-	if err != nil {
-		return zeroValue, fmt.Errorf("insufficient key map: %w", err)
-	}
-
 	m := NewModel(s)
 
-	p := tea.NewProgram(m, tea.WithOutput(s.Output), tea.WithInput(s.Input))
+	p := tea.NewProgram(m, s.programOptions()...)
 
-	_, err = p.Run()
+	_, err := p.Run()
 	if err != nil {
 		return zeroValue, fmt.Errorf("running prompt: %w", err)
 	}
 
+	s.printResultView(m)
+
 	return m.Value()
 }
 
+// programOptions returns the options of the bubbletea program that runs the
+// prompt.
+func (s *Selection[T]) programOptions() []tea.ProgramOption {
+	options := []tea.ProgramOption{tea.WithOutput(s.Output), tea.WithInput(s.Input)}
+
+	if s.EnableMouse {
+		options = append(options, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
+
+	return options
+}
+
+// printResultView prints the result view after the prompt has concluded in
+// case it was displayed on the alternate screen, where the final view of the
+// program vanishes.
+func (s *Selection[T]) printResultView(m tea.Model) {
+	if s.EnableMouse {
+		fmt.Fprint(s.Output, m.View())
+	}
+}
+
 // FilterContainsCaseInsensitive returns true if the string representation of
 // the choice contains the filter string without regard for capitalization.
 func FilterContainsCaseInsensitive[T any](filter string, choice *Choice[T]) bool {
@@ -0,0 +1,156 @@
+package selection
+
+import (
+	"strings"
+)
+
+// Query is a parsed filter query in an extended search syntax similar to the
+// one of fzf. A query consists of space-separated terms which all have to
+// match. Terms can be modified as follows:
+//
+//   - term: The text fuzzily matches the term as described in FuzzyMatch.
+//   - 'term: The text contains the term.
+//   - ^term: The text starts with the term.
+//   - term$: The text ends with the term.
+//   - !term: The text does not contain the term. Negation can be combined
+//     with anchors, such as in !^term or !term$.
+//   - term1 | term2: Either of the terms has to match.
+//
+// All terms are matched without regard for capitalization.
+type Query struct {
+	// groups holds the terms of which at least one has to match for each
+	// group.
+	groups [][]queryTerm
+}
+
+type queryTerm struct {
+	text     string
+	fuzzy    bool
+	prefix   bool
+	suffix   bool
+	negation bool
+}
+
+// ParseQuery parses a filter query as described in Query.
+func ParseQuery(query string) *Query {
+	q := &Query{}
+	alternative := false
+
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		if field == "|" {
+			alternative = len(q.groups) > 0
+
+			continue
+		}
+
+		term, ok := parseQueryTerm(field)
+		if !ok {
+			continue
+		}
+
+		if alternative {
+			last := len(q.groups) - 1
+			q.groups[last] = append(q.groups[last], term)
+			alternative = false
+
+			continue
+		}
+
+		q.groups = append(q.groups, []queryTerm{term})
+	}
+
+	return q
+}
+
+func parseQueryTerm(field string) (queryTerm, bool) {
+	term := queryTerm{}
+
+	if strings.HasPrefix(field, "!") {
+		term.negation = true
+		field = field[1:]
+	}
+
+	exact := false
+
+	switch {
+	case strings.HasPrefix(field, "'"):
+		exact = true
+		field = field[1:]
+	case strings.HasPrefix(field, "^"):
+		term.prefix = true
+		field = field[1:]
+	}
+
+	if len(field) > 1 && strings.HasSuffix(field, "$") {
+		term.suffix = true
+		field = field[:len(field)-1]
+	}
+
+	// like in fzf, negated and anchored terms are matched exactly
+	term.fuzzy = !exact && !term.negation && !term.prefix && !term.suffix
+	term.text = field
+
+	return term, field != ""
+}
+
+// Matches returns true if the text matches the query. An empty query matches
+// every text.
+func (q *Query) Matches(text string) bool {
+	text = strings.ToLower(text)
+
+	for _, group := range q.groups {
+		if !matchesAny(text, group) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesAny(text string, alternatives []queryTerm) bool {
+	for _, alternative := range alternatives {
+		if alternative.matches(text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t queryTerm) matches(text string) bool {
+	var matched bool
+
+	switch {
+	case t.prefix && t.suffix:
+		matched = text == t.text
+	case t.prefix:
+		matched = strings.HasPrefix(text, t.text)
+	case t.suffix:
+		matched = strings.HasSuffix(text, t.text)
+	case t.fuzzy:
+		_, _, matched = FuzzyMatch(t.text, text)
+	default:
+		matched = strings.Contains(text, t.text)
+	}
+
+	return matched != t.negation
+}
+
+// FilterQuery returns a filter that matches choices whose string representation
+// matches the filter text as a query as described in Query. The query is only
+// parsed once per filter text and not for each individual choice.
+func FilterQuery[T any]() func(filterText string, choice *Choice[T]) bool {
+	var (
+		parsedText string
+		query      = ParseQuery("")
+	)
+
+	return func(filterText string, choice *Choice[T]) bool {
+		if filterText != parsedText {
+			parsedText = filterText
+			query = ParseQuery(filterText)
+		}
+
+		return query.Matches(choice.String)
+	}
+}
@@ -0,0 +1,63 @@
+package selection_test
+
+import (
+	"testing"
+
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+)
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		query   string
+		text    string
+		matched bool
+	}{
+		{query: "", text: "anything", matched: true},
+		{query: "wrkr", text: "worker-eu", matched: true},
+		{query: "'wrkr", text: "worker-eu", matched: false},
+		{query: "'ker-", text: "Worker-EU", matched: true},
+		{query: "^work", text: "worker-eu", matched: true},
+		{query: "^eu", text: "worker-eu", matched: false},
+		{query: "eu$", text: "worker-eu", matched: true},
+		{query: "^worker-eu$", text: "worker-eu", matched: true},
+		{query: "^worker$", text: "worker-eu", matched: false},
+		{query: "!eu", text: "worker-eu", matched: false},
+		{query: "!us", text: "worker-eu", matched: true},
+		{query: "!^work", text: "worker-eu", matched: false},
+		{query: "work !us$", text: "worker-eu", matched: true},
+		{query: "work !eu$", text: "worker-eu", matched: false},
+		{query: "us$ | eu$", text: "worker-eu", matched: true},
+		{query: "us$ | ap$", text: "worker-eu", matched: false},
+		{query: "^db us$ | eu$", text: "db-eu", matched: true},
+		{query: "^db us$ | eu$", text: "worker-eu", matched: false},
+		{query: "| eu", text: "worker-eu", matched: true},
+	}
+
+	for _, testCase := range testCases {
+		matched := selection.ParseQuery(testCase.query).Matches(testCase.text)
+		if matched != testCase.matched {
+			t.Errorf("query %q matching %q: got %v, expected %v",
+				testCase.query, testCase.text, matched, testCase.matched)
+		}
+	}
+}
+
+func TestFilterQuery(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"worker-eu", "worker-us", "db-eu", "db-us", "cache-ap"})
+	s.Filter = selection.FilterQuery[string]()
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, test.MsgsFromText("!db eu$ | ap$")...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "filter_query.golden")
+
+	if choice := getChoice(t, m); choice != "worker-eu" {
+		t.Errorf("unexpected choice: %q, expected worker-eu", choice)
+	}
+}
@@ -0,0 +1,54 @@
+package selection
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quickSelectLabels are the labels of the displayed choices in quick-select
+// mode in the order in which they are assigned.
+const quickSelectLabels = "123456789abcdefghijklmnopqrstuvwxyz"
+
+// isQuickSelectActive returns true if the choices can currently be selected by
+// their label, which is the case if quick-select is enabled and the filter
+// input is not focused.
+func (m *Model[T]) isQuickSelectActive() bool {
+	return m.QuickSelect && !m.filterInput.Focused()
+}
+
+// quickSelectLabel returns the label of the displayed choice with the given
+// index or an empty string if it does not have a label.
+func (m *Model[T]) quickSelectLabel(idx int) string {
+	if idx < 0 || idx >= len(quickSelectLabels) || idx >= len(m.currentChoices) {
+		return ""
+	}
+
+	return quickSelectLabels[idx : idx+1]
+}
+
+// quickSelectIndex returns the index of the displayed choice whose label was
+// pressed or -1 if the key is not the label of a displayed choice.
+func (m *Model[T]) quickSelectIndex(msg tea.KeyMsg) int {
+	if !m.isQuickSelectActive() || msg.Type != tea.KeyRunes || len(msg.Runes) != 1 {
+		return -1
+	}
+
+	for idx, label := range quickSelectLabels {
+		if label == msg.Runes[0] && idx < len(m.currentChoices) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// quickSelectFilterPlaceholder returns the placeholder of the filter input
+// which explains how the filter input is focused in quick-select mode.
+func (m *Model[T]) quickSelectFilterPlaceholder() string {
+	if len(m.KeyMap.FocusFilter) == 0 {
+		return m.FilterPlaceholder
+	}
+
+	return fmt.Sprintf("Press %s to filter choices", m.KeyMap.FocusFilter[0])
+}
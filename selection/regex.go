@@ -0,0 +1,120 @@
+package selection
+
+import (
+	"errors"
+	"regexp"
+	"regexp/syntax"
+	"unicode/utf8"
+)
+
+// regexFilterFlags are prepended to the filter text in regex mode such that
+// it matches without regard for capitalization like the default filter.
+const regexFilterFlags = "(?i)"
+
+// filterCache is implemented by the choice sources of this package that cache
+// the choices that match the filter text.
+type filterCache interface {
+	invalidateFilterCache()
+}
+
+// isRegexFilterSupported returns true if filtering is enabled and the choice
+// source filters using the model such that regular expressions can be used.
+func (m *Model[T]) isRegexFilterSupported() bool {
+	_, ok := m.source.(filterCache)
+
+	return ok && m.isFilterEnabled()
+}
+
+// isRegexFilterActive returns true if the filter text is currently interpreted
+// as regular expression.
+func (m *Model[T]) isRegexFilterActive() bool {
+	return m.RegexFilter && m.isRegexFilterSupported()
+}
+
+// toggleRegexFilter switches the filter input between the configured filter
+// and regex mode.
+func (m *Model[T]) toggleRegexFilter() {
+	highlighted := m.highlightedChoice()
+
+	m.RegexFilter = !m.RegexFilter
+	m.filterRegexp = nil
+	m.compileFilterRegexp()
+
+	cache, ok := m.source.(filterCache)
+	if ok {
+		cache.invalidateFilterCache()
+	}
+
+	m.applyFilter(highlighted)
+}
+
+// compileFilterRegexp compiles the filter text in regex mode. If the filter
+// text is not a valid regular expression, the error is displayed and the last
+// valid regular expression stays in effect such that the displayed choices do
+// not disappear while a pattern is being typed.
+func (m *Model[T]) compileFilterRegexp() {
+	m.filterRegexpErr = nil
+
+	if !m.isRegexFilterActive() {
+		return
+	}
+
+	re, err := regexp.Compile(regexFilterFlags + m.filterText())
+	if err != nil {
+		m.filterRegexpErr = err
+
+		return
+	}
+
+	m.filterRegexp = re
+}
+
+// matchesRegexFilter returns true if the string representation of the choice
+// matches the last valid regular expression of the filter input.
+func (m *Model[T]) matchesRegexFilter(choice *Choice[T]) bool {
+	return m.filterRegexp == nil || m.filterRegexp.MatchString(choice.String)
+}
+
+// regexMatchPositions returns the positions of the runes in the string
+// representation of the choice that are part of the leftmost match of the
+// regular expression.
+func (m *Model[T]) regexMatchPositions(choice *Choice[T]) []int {
+	if m.filterRegexp == nil {
+		return nil
+	}
+
+	loc := m.filterRegexp.FindStringIndex(choice.String)
+	if loc == nil {
+		return nil
+	}
+
+	start := utf8.RuneCountInString(choice.String[:loc[0]])
+	length := utf8.RuneCountInString(choice.String[loc[0]:loc[1]])
+
+	positions := make([]int, 0, length)
+	for i := 0; i < length; i++ {
+		positions = append(positions, start+i)
+	}
+
+	return positions
+}
+
+// filterError returns a short description of why no choice could be created
+// from the filter text or why it is not a valid regular expression. An empty
+// string is returned if there is no such error.
+func (m *Model[T]) filterError() string {
+	if m.createChoiceErr != nil {
+		return m.createChoiceErr.Error()
+	}
+
+	if m.filterRegexpErr == nil {
+		return ""
+	}
+
+	var syntaxErr *syntax.Error
+	if errors.As(m.filterRegexpErr, &syntaxErr) {
+		return "invalid regex: " + syntaxErr.Code.String()
+	}
+
+	return m.filterRegexpErr.Error()
+}
@@ -0,0 +1,75 @@
+package selection
+
+import (
+	"strings"
+)
+
+const (
+	scoreMidWordMatch      = 1
+	scoreWordBoundaryMatch = 2
+	scorePrefixMatch       = 3
+)
+
+// ScoreContainsCaseInsensitive is a scoring filter that matches choices whose
+// string representation contains the filter text without regard for
+// capitalization, just like FilterContainsCaseInsensitive. Matches at the
+// start of the string rank above matches at the start of a word, which in turn
+// rank above matches in the middle of a word.
+func ScoreContainsCaseInsensitive[T any](filter string, choice *Choice[T]) (int, bool) {
+	text := []rune(choice.String)
+	lowerText := []rune(strings.ToLower(choice.String))
+	lowerFilter := []rune(strings.ToLower(filter))
+
+	// case folding may change the number of runes in which case the word
+	// boundaries cannot be determined reliably
+	if len(text) != len(lowerText) {
+		if strings.Contains(string(lowerText), string(lowerFilter)) {
+			return scoreMidWordMatch, true
+		}
+
+		return 0, false
+	}
+
+	best, matched := 0, false
+
+	for pos := 0; pos+len(lowerFilter) <= len(lowerText); pos++ {
+		if !hasRunePrefix(lowerText[pos:], lowerFilter) {
+			continue
+		}
+
+		matched = true
+
+		switch {
+		case pos == 0:
+			return scorePrefixMatch, true
+		case isWordBoundary(text, pos):
+			best = scoreWordBoundaryMatch
+		default:
+			best = max(best, scoreMidWordMatch)
+		}
+	}
+
+	return best, matched
+}
+
+// ScoreFuzzy is a scoring filter that fuzzily matches the filter text as
+// described in FuzzyMatch and ranks the choices by their fuzzy match score.
+func ScoreFuzzy[T any](filter string, choice *Choice[T]) (int, bool) {
+	score, _, matched := FuzzyMatch(filter, choice.String)
+
+	return score, matched
+}
+
+func hasRunePrefix(text []rune, prefix []rune) bool {
+	if len(prefix) > len(text) {
+		return false
+	}
+
+	for i, r := range prefix {
+		if text[i] != r {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,56 @@
+package selection_test
+
+import (
+	"testing"
+
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func TestScoreContainsCaseInsensitive(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		filter  string
+		text    string
+		score   int
+		matched bool
+	}{
+		{filter: "dev", text: "Development", score: 3, matched: true},
+		{filter: "dev", text: "my-dev", score: 2, matched: true},
+		{filter: "dev", text: "myDev", score: 2, matched: true},
+		{filter: "dev", text: "undevelop", score: 1, matched: true},
+		{filter: "dev", text: "undevelop dev", score: 2, matched: true},
+		{filter: "dev", text: "prod", score: 0, matched: false},
+	}
+
+	for _, testCase := range testCases {
+		score, matched := selection.ScoreContainsCaseInsensitive(testCase.filter,
+			selection.NewChoice(0, testCase.text))
+
+		if score != testCase.score || matched != testCase.matched {
+			t.Errorf("scoring %q with %q: got (%d, %v), expected (%d, %v)",
+				testCase.text, testCase.filter, score, matched, testCase.score, testCase.matched)
+		}
+	}
+}
+
+func TestScoreFilter(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"undevelop", "my-dev", "prod", "devbox", "Other dev"})
+	s.ScoreFilter = selection.ScoreContainsCaseInsensitive[string]
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m, test.MsgsFromText("dev")...)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "score_filter.golden")
+
+	// the highlighted choice still matches although it is ranked last
+	if choice := getChoice(t, m); choice != "undevelop" {
+		t.Errorf("unexpected choice: %q, expected undevelop", choice)
+	}
+}
@@ -0,0 +1,181 @@
+package selection
+
+import "sort"
+
+// ChoiceSource provides the choices of a selection. Implementations can be
+// used to browse huge or lazily loaded choice sets, such as database rows or
+// log lines, since the selection only ever requests the choices that are
+// actually displayed. The choices returned by a source should be created with
+// NewChoice with their position within the source as index.
+type ChoiceSource[T any] interface {
+	// Len returns the total number of choices that are displayed when no
+	// filter is applied.
+	Len() int
+
+	// Range returns up to limit choices starting at the given offset.
+	Range(offset int, limit int) []*Choice[T]
+
+	// Query returns up to limit choices that match the filter text starting
+	// with the match at the given offset. Additionally, it returns the total
+	// number of choices that match the filter text. It is only called when
+	// filtering is enabled and the filter text is not empty.
+	Query(filterText string, offset int, limit int) (choices []*Choice[T], available int)
+}
+
+// sliceSource is the ChoiceSource for choices that are provided as a slice. It
+// is used by selections that are created with New.
+type sliceSource[T any] struct {
+	choices []*Choice[T]
+
+	// match decides whether a choice matches the filter text, it is set by
+	// the model according to its filter configuration.
+	match func(filterText string, choice *Choice[T]) bool
+
+	// score optionally ranks the matching choices, it is set by the model if
+	// a score filter is configured. Choices with equal scores keep their
+	// original order.
+	score func(filterText string, choice *Choice[T]) (int, bool)
+
+	// filtered caches the indices of the choices that match filterText such
+	// that the choices are only filtered once per filter text and not each
+	// time the selection is scrolled.
+	filtered       []int
+	filterText     string
+	filteredCached bool
+}
+
+var _ ChoiceSource[any] = &sliceSource[any]{}
+
+func newSliceSource[T any](choices []T) *sliceSource[T] {
+	return &sliceSource[T]{choices: asChoices(choices)}
+}
+
+// newSliceSourceFromChoices creates a slice source from a copy of the given
+// slice such that reordering and adding choices does not affect the slice.
+func newSliceSourceFromChoices[T any](choices []*Choice[T]) *sliceSource[T] {
+	return &sliceSource[T]{choices: append([]*Choice[T](nil), choices...)}
+}
+
+func (s *sliceSource[T]) Len() int {
+	return len(s.choices)
+}
+
+func (s *sliceSource[T]) Range(offset int, limit int) []*Choice[T] {
+	offset = max(0, min(offset, len(s.choices)))
+
+	return s.choices[offset:min(len(s.choices), offset+max(0, limit))]
+}
+
+func (s *sliceSource[T]) Query(
+	filterText string, offset int, limit int,
+) (choices []*Choice[T], available int) {
+	if !s.filteredCached || s.filterText != filterText {
+		s.filterText = filterText
+		s.filteredCached = true
+
+		if s.score != nil {
+			s.filtered = s.rank(s.filtered[:0])
+		} else {
+			s.filtered = s.filter(s.choices, s.filtered[:0])
+		}
+	}
+
+	offset = max(0, min(offset, len(s.filtered)))
+	page := s.filtered[offset:min(len(s.filtered), offset+max(0, limit))]
+
+	choices = make([]*Choice[T], 0, len(page))
+	for _, idx := range page {
+		choices = append(choices, s.choices[idx])
+	}
+
+	return choices, len(s.filtered)
+}
+
+// filter appends the indices of the given choices that match the cached
+// filter text to the indices slice.
+func (s *sliceSource[T]) filter(choices []*Choice[T], indices []int) []int {
+	for _, choice := range choices {
+		if s.match != nil && !s.match(s.filterText, choice) {
+			continue
+		}
+
+		indices = append(indices, choice.idx)
+	}
+
+	return indices
+}
+
+// rank appends the indices of all choices that match the cached filter text
+// to the indices slice, ordered by their score in descending order.
+func (s *sliceSource[T]) rank(indices []int) []int {
+	scores := make(map[int]int)
+
+	for _, choice := range s.choices {
+		score, ok := s.score(s.filterText, choice)
+		if !ok {
+			continue
+		}
+
+		scores[choice.idx] = score
+		indices = append(indices, choice.idx)
+	}
+
+	sort.SliceStable(indices, func(i, j int) bool {
+		return scores[indices[i]] > scores[indices[j]]
+	})
+
+	return indices
+}
+
+// invalidateFilterCache has to be called when the filter behavior changes
+// without a change of the filter text.
+func (s *sliceSource[T]) invalidateFilterCache() {
+	s.filteredCached = false
+}
+
+func (s *sliceSource[T]) append(newChoices []*Choice[T]) {
+	for i, choice := range newChoices {
+		choice.idx = len(s.choices) + i
+	}
+
+	s.choices = append(s.choices, newChoices...)
+
+	// only the new choices need to be filtered since they are appended,
+	// unless they have to be ranked among the previously filtered choices
+	switch {
+	case !s.filteredCached:
+	case s.score != nil:
+		s.invalidateFilterCache()
+	default:
+		s.filtered = s.filter(newChoices, s.filtered)
+	}
+}
+
+// position returns the position of the choice among the choices that match the
+// filter text.
+func (s *sliceSource[T]) position(filterText string, choice *Choice[T]) (int, bool) {
+	if choice.idx < 0 || choice.idx >= len(s.choices) || s.choices[choice.idx] != choice {
+		return 0, false
+	}
+
+	if filterText == "" {
+		return choice.idx, true
+	}
+
+	// make sure that the filtered choices are cached
+	s.Query(filterText, 0, 0)
+
+	for position, idx := range s.filtered {
+		if idx == choice.idx {
+			return position, true
+		}
+	}
+
+	return 0, false
+}
+
+func (s *sliceSource[T]) reindex() {
+	for i, choice := range s.choices {
+		choice.idx = i
+	}
+}
@@ -0,0 +1,196 @@
+package selection_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+// numberSource is a lazily evaluated choice source of consecutive numbers that
+// keeps track of how many choices it created.
+type numberSource struct {
+	n       int
+	created int
+}
+
+var _ selection.ChoiceSource[int] = &numberSource{}
+
+func (s *numberSource) Len() int {
+	return s.n
+}
+
+func (s *numberSource) Range(offset int, limit int) []*selection.Choice[int] {
+	choices := []*selection.Choice[int]{}
+
+	for i := offset; i < s.n && len(choices) < limit; i++ {
+		choices = append(choices, selection.NewChoice(i, i))
+		s.created++
+	}
+
+	return choices
+}
+
+// Query matches all numbers that end with the filter text. The matches are
+// calculated directly instead of checking each number.
+func (s *numberSource) Query(filter string, offset int, limit int) ([]*selection.Choice[int], int) {
+	var suffix int
+
+	_, err := fmt.Sscanf(filter, "%d", &suffix)
+	if err != nil || strings.HasPrefix(filter, "-") {
+		return nil, 0
+	}
+
+	step := 1
+	for range filter {
+		step *= 10
+	}
+
+	available := 0
+	if suffix < s.n {
+		available = (s.n-suffix-1)/step + 1
+	}
+
+	choices := []*selection.Choice[int]{}
+
+	for i := offset; i < available && len(choices) < limit; i++ {
+		choices = append(choices, selection.NewChoice(suffix+i*step, suffix+i*step))
+		s.created++
+	}
+
+	return choices, available
+}
+
+func TestChoiceSource(t *testing.T) {
+	t.Parallel()
+
+	source := &numberSource{n: 1_000_000}
+
+	m := selection.NewModel(selection.NewFromSource[int]("foo:", source))
+	m.PageSize = 3
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, tea.KeyDown, tea.KeyDown, tea.KeyDown, tea.KeyPgDown)
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "source.golden")
+
+	if choice := getChoice(t, m); choice != 3 {
+		t.Errorf("unexpected choice: %v, expected 3", choice)
+	}
+
+	test.Update(t, m, tea.KeyUp)
+
+	if choice := getChoice(t, m); choice != 2 {
+		t.Errorf("unexpected choice: %v, expected 2", choice)
+	}
+
+	for _, msg := range append(test.MsgsFromText("42"), tea.KeyDown) {
+		test.Update(t, m, msg)
+	}
+
+	test.AssertGoldenView(t, m, "source_filtered.golden")
+
+	if choice := getChoice(t, m); choice != 142 {
+		t.Errorf("unexpected choice: %v, expected 142", choice)
+	}
+
+	if source.created > 100 {
+		t.Errorf("%d choices were created to display only a few choices", source.created)
+	}
+}
+
+func TestChoiceSourceUnpaged(t *testing.T) {
+	t.Parallel()
+
+	source := &numberSource{n: 5_000_000}
+
+	m := selection.NewModel(selection.NewFromSource[int]("foo:", source))
+
+	// without a page size, only a page of choices is queried until the
+	// terminal size is known
+	test.Run(t, m)
+	assertNoError(t, m)
+
+	if source.created > 1000 {
+		t.Fatalf("%d choices were created before the terminal size was known", source.created)
+	}
+
+	source.created = 0
+
+	test.Update(t, m, tea.WindowSizeMsg{Width: 80, Height: 10})
+
+	if source.created > 1000 {
+		t.Errorf("%d choices were created to fit the terminal", source.created)
+	}
+
+	if height := strings.Count(m.View(), "\n"); height >= 10 {
+		t.Errorf("view with %d lines does not fit the terminal", height)
+	}
+}
+
+func TestFilterOncePerFilterChange(t *testing.T) {
+	t.Parallel()
+
+	choices := make([]string, 100)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("choice%d", i)
+	}
+
+	filterCalls := 0
+
+	m := selection.NewModel(selection.New("foo:", choices))
+	m.PageSize = 5
+	m.Filter = func(filter string, choice *selection.Choice[string]) bool {
+		filterCalls++
+
+		return selection.FilterContainsCaseInsensitive(filter, choice)
+	}
+
+	test.Run(t, m, test.MsgsFromText("1")...)
+	assertNoError(t, m)
+
+	if filterCalls != len(choices) {
+		t.Fatalf("filter was called %d times instead of %d", filterCalls, len(choices))
+	}
+
+	for _, key := range []tea.KeyType{
+		tea.KeyDown, tea.KeyDown, tea.KeyPgDown, tea.KeyPgDown, tea.KeyUp, tea.KeyPgUp,
+	} {
+		test.Update(t, m, key)
+	}
+
+	if filterCalls != len(choices) {
+		t.Fatalf("scrolling caused the filter to be called %d more times",
+			filterCalls-len(choices))
+	}
+}
+
+func BenchmarkScroll(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000} {
+		choices := make([]string, n)
+		for i := range choices {
+			choices[i] = fmt.Sprintf("choice%d", i)
+		}
+
+		for _, filter := range []string{"", "1"} {
+			b.Run(fmt.Sprintf("choices=%d/filter=%q", n, filter), func(b *testing.B) {
+				m := selection.NewModel(selection.New("foo:", choices))
+				m.PageSize = 10
+				m.LoopCursor = true
+
+				test.Run(b, m, test.MsgsFromText(filter)...)
+				assertNoError(b, m)
+
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					test.Update(b, m, tea.KeyDown)
+				}
+			})
+		}
+	}
+}
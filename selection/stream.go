@@ -0,0 +1,97 @@
+package selection
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// streamBatchWindow is the maximum duration for which choices from the
+	// choice stream are collected before they are added to the selection.
+	streamBatchWindow = 50 * time.Millisecond
+
+	// streamBatchSize is the maximum number of choices that are collected from
+	// the choice stream before they are added to the selection.
+	streamBatchSize = 1000
+)
+
+// choiceStreamMsg transports a batch of streamed choices to the model.
+type choiceStreamMsg[T any] struct {
+	choices []T
+	done    bool
+}
+
+// receiveChoices returns a command that waits for the next batch of choices
+// from the choice stream. Choices that arrive in quick succession are batched
+// in order to avoid re-filtering and re-rendering for each individual choice.
+func (m *Model[T]) receiveChoices() tea.Cmd {
+	stream := m.ChoiceStream
+
+	return func() tea.Msg {
+		first, ok := <-stream
+		if !ok {
+			return choiceStreamMsg[T]{done: true}
+		}
+
+		batch := []T{first}
+		deadline := time.After(streamBatchWindow)
+
+		for len(batch) < streamBatchSize {
+			select {
+			case choice, ok := <-stream:
+				if !ok {
+					return choiceStreamMsg[T]{choices: batch, done: true}
+				}
+
+				batch = append(batch, choice)
+			case <-deadline:
+				return choiceStreamMsg[T]{choices: batch}
+			}
+		}
+
+		return choiceStreamMsg[T]{choices: batch}
+	}
+}
+
+// addStreamedChoices adds a batch of streamed choices to the selection while
+// keeping the cursor on the currently highlighted choice, even if the streamed
+// choices are ranked before it by the ScoreFilter.
+func (m *Model[T]) addStreamedChoices(msg choiceStreamMsg[T]) tea.Cmd {
+	src, ok := m.source.(*sliceSource[T])
+	if !ok {
+		return nil
+	}
+
+	highlighted := m.highlightedChoice()
+
+	// in table mode, the string representations of the new choices have to
+	// be set before they are filtered
+	choices := asChoices(msg.choices)
+	m.addTableRows(choices)
+	src.append(choices)
+
+	for _, choice := range choices {
+		m.indexHotkey(choice)
+	}
+
+	if m.Grid {
+		m.updateGridCellWidth(src.Range(src.Len()-len(msg.choices), len(msg.choices)))
+	}
+
+	m.loading = !msg.done
+
+	if m.height > 0 {
+		m.forceUpdatePageSizeForHeight()
+	}
+
+	m.applyFilter(highlighted)
+
+	m.highlightInitialChoice(src.Range(src.Len()-len(msg.choices), len(msg.choices)))
+
+	if msg.done {
+		return nil
+	}
+
+	return m.receiveChoices()
+}
@@ -0,0 +1,218 @@
+package selection
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func TestChoiceStream(t *testing.T) {
+	t.Parallel()
+
+	stream := make(chan string, 3)
+
+	s := New("foo:", []string{})
+	s.ChoiceStream = stream
+	s.ColorProfile = termenv.TrueColor
+	m := NewModel(s)
+
+	test.Run(t, m)
+
+	if m.Err != nil {
+		t.Fatalf("model contains error: %v", m.Err)
+	}
+
+	test.AssertGoldenView(t, m, "stream_empty.golden")
+
+	stream <- "a"
+	stream <- "b"
+
+	test.Update(t, m, m.receiveChoices()())
+	test.Update(t, m, tea.KeyDown)
+	test.AssertGoldenView(t, m, "stream_loading.golden")
+
+	stream <- "c"
+	close(stream)
+
+	cmd := test.Update(t, m, m.receiveChoices()())
+	if cmd != nil {
+		t.Fatalf("closed stream produced another receive command")
+	}
+
+	test.AssertGoldenView(t, m, "stream_done.golden")
+
+	choice, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice != "b" {
+		t.Errorf("streamed choices moved the cursor to %q instead of keeping it on b", choice)
+	}
+}
+
+func TestChoiceStreamInitialChoice(t *testing.T) {
+	t.Parallel()
+
+	stream := make(chan string, 3)
+
+	s := New("foo:", []string{"a"})
+	s.ChoiceStream = stream
+	s.InitialChoice = func(choice string) bool { return choice == "c" }
+	m := NewModel(s)
+
+	test.Run(t, m)
+
+	if m.Err != nil {
+		t.Fatalf("model contains error: %v", m.Err)
+	}
+
+	stream <- "b"
+	stream <- "c"
+	close(stream)
+
+	test.Update(t, m, m.receiveChoices()())
+
+	choice, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice != "c" {
+		t.Errorf("unexpected choice: %q, expected streamed initial choice c", choice)
+	}
+}
+
+func TestChoiceStreamInitialChoiceAfterCursorMove(t *testing.T) {
+	t.Parallel()
+
+	stream := make(chan string, 3)
+
+	s := New("foo:", []string{"a", "b"})
+	s.ChoiceStream = stream
+	s.InitialChoice = func(choice string) bool { return choice == "c" }
+	m := NewModel(s)
+
+	test.Run(t, m, tea.KeyDown)
+
+	stream <- "c"
+	close(stream)
+
+	test.Update(t, m, m.receiveChoices()())
+
+	choice, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice != "b" {
+		t.Errorf("streamed initial choice moved the cursor to %q instead of keeping it on b", choice)
+	}
+}
+
+func TestChoiceStreamInitialChoiceAfterMultiHotkey(t *testing.T) {
+	t.Parallel()
+
+	stream := make(chan string, 3)
+
+	a, b := NewChoice(0, "a"), NewChoice(1, "b")
+	b.Hotkey = "ctrl+b"
+
+	s := NewMultiFromChoices("foo:", []*Choice[string]{a, b})
+	s.ChoiceStream = stream
+	s.InitialChoice = func(choice string) bool { return choice == "c" }
+	m := NewMultiModel(s)
+
+	test.Run(t, m, tea.KeyMsg{Type: tea.KeyCtrlB})
+
+	stream <- "c"
+	close(stream)
+
+	test.Update(t, m, m.receiveChoices()())
+
+	choice, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice != "b" {
+		t.Errorf("streamed initial choice moved the cursor to %q instead of keeping it on b", choice)
+	}
+}
+
+func TestChoiceStreamScoreFilter(t *testing.T) {
+	t.Parallel()
+
+	stream := make(chan string, 1)
+
+	s := New("foo:", []string{"my-dev", "undevelop"})
+	s.ChoiceStream = stream
+	s.ScoreFilter = ScoreContainsCaseInsensitive[string]
+	m := NewModel(s)
+
+	test.Run(t, m, append(test.MsgsFromText("dev"), tea.KeyDown)...)
+
+	// the streamed choice is ranked before the highlighted one
+	stream <- "devbox"
+	close(stream)
+
+	test.Update(t, m, m.receiveChoices()())
+
+	choice, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice != "undevelop" {
+		t.Errorf("streamed choices moved the cursor to %q instead of keeping it on undevelop", choice)
+	}
+}
+
+func TestChoiceStreamTable(t *testing.T) {
+	t.Parallel()
+
+	stream := make(chan string, 2)
+	evaluated := 0
+
+	s := New("foo:", []string{"a"})
+	s.ChoiceStream = stream
+	s.Columns = []Column[string]{
+		{Header: "NAME", Value: func(name string) string {
+			evaluated++
+
+			return name
+		}},
+		{Header: "LENGTH", Value: func(name string) string { return fmt.Sprint(len(name)) }},
+	}
+	m := NewModel(s)
+
+	test.Run(t, m, test.MsgsFromText("long")...)
+
+	stream <- "b"
+	test.Update(t, m, m.receiveChoices()())
+
+	stream <- "a-long-name"
+	close(stream)
+	test.Update(t, m, m.receiveChoices()())
+
+	choice, err := m.Value()
+	if err != nil {
+		t.Fatalf("value: %v", err)
+	}
+
+	if choice != "a-long-name" {
+		t.Errorf("unexpected choice: %q, expected a-long-name", choice)
+	}
+
+	if m.tableHeader != "NAME         LENGTH" {
+		t.Errorf("columns were not widened for the streamed choices: %q", m.tableHeader)
+	}
+
+	// the cells of each choice are only determined once
+	if evaluated != 3 {
+		t.Errorf("cells were determined %d times for 3 choices", evaluated)
+	}
+}
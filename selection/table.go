@@ -0,0 +1,315 @@
+package selection
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/muesli/reflow/ansi"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	// tableColumnGap is the number of spaces between the columns of a table.
+	tableColumnGap = 2
+	// tableMinColumnWidth is the width below which columns are not truncated
+	// any further to fit the terminal width.
+	tableMinColumnWidth = 3
+	// tableEllipsis marks truncated cells.
+	tableEllipsis = "…"
+	// defaultRowPrefixWidth is the width of the scroll hint and the cursor in
+	// front of each choice in the default template.
+	defaultRowPrefixWidth = 4
+	// quickSelectLabelWidth is the width of the quick-select label in front of
+	// each choice in the default template.
+	quickSelectLabelWidth = 2
+	// checkboxWidth is the width of the check box in front of each choice in
+	// the default multi-selection template.
+	checkboxWidth = 2
+)
+
+// Column is a column of a selection that displays its choices as table (see
+// Selection.Columns).
+type Column[T any] struct {
+	// Header is displayed above the column and identifies the column for
+	// Selection.SortColumn.
+	Header string
+
+	// Value extracts the content of the column from the value of a choice.
+	Value func(T) string
+
+	// Less optionally determines the order of the values when the choices are
+	// sorted by the column. By default, the contents of the column are
+	// compared as strings.
+	Less func(a T, b T) bool
+}
+
+// isTable returns true if the choices are displayed as table.
+func (m *Model[T]) isTable() bool {
+	return len(m.Columns) > 0
+}
+
+// initTable validates the table configuration and sorts the choices by the
+// SortColumn.
+func (m *Model[T]) initTable() error {
+	src, ok := m.source.(*sliceSource[T])
+	if !ok {
+		return fmt.Errorf("table mode is not supported with custom choice sources")
+	}
+
+	if m.Grid {
+		return fmt.Errorf("table mode cannot be combined with grid mode")
+	}
+
+	sortColumn := -1
+
+	for i, column := range m.Columns {
+		if column.Value == nil {
+			return fmt.Errorf("column %q has no value function", column.Header)
+		}
+
+		if m.SortColumn != "" && column.Header == m.SortColumn {
+			sortColumn = i
+		}
+	}
+
+	if m.SortColumn == "" {
+		return nil
+	}
+
+	if sortColumn < 0 {
+		return fmt.Errorf("unknown sort column %q", m.SortColumn)
+	}
+
+	column := m.Columns[sortColumn]
+
+	less := column.Less
+	if less == nil {
+		less = func(a T, b T) bool {
+			return column.Value(a) < column.Value(b)
+		}
+	}
+
+	sort.SliceStable(src.choices, func(i, j int) bool {
+		if m.SortDescending {
+			return less(src.choices[j].Value, src.choices[i].Value)
+		}
+
+		return less(src.choices[i].Value, src.choices[j].Value)
+	})
+
+	return nil
+}
+
+// updateTable sets the string representation of each choice to its cells
+// separated by spaces, such that the choices are filtered by their untruncated
+// cells, and aligns the columns such that the rows fit the terminal width. It
+// has to be called whenever choices are removed or updated.
+func (m *Model[T]) updateTable() {
+	src, ok := m.source.(*sliceSource[T])
+	if !m.isTable() || !ok {
+		return
+	}
+
+	m.tableContentWidths = make([]int, len(m.Columns))
+	for i, column := range m.Columns {
+		m.tableContentWidths[i] = ansi.PrintableRuneWidth(column.Header)
+	}
+
+	m.tableHotkeyWidth = 0
+
+	// the filter results depend on the string representation
+	if m.addTableRows(src.choices) {
+		src.invalidateFilterCache()
+	}
+}
+
+// addTableRows sets the string representations of the given choices to their
+// cells and widens the columns such that the cells fit. The cells are cached
+// such that they are only determined once per choice. It returns true if the
+// string representation of any choice changed.
+func (m *Model[T]) addTableRows(choices []*Choice[T]) bool {
+	if !m.isTable() {
+		return false
+	}
+
+	changed := false
+
+	for _, choice := range choices {
+		if choice.tableCells == nil {
+			choice.tableCells = m.tableCells(choice.Value)
+
+			str := strings.Join(choice.tableCells, " ")
+			if choice.String != str {
+				choice.String = str
+				changed = true
+			}
+		}
+
+		for i, cell := range choice.tableCells {
+			m.tableContentWidths[i] = max(m.tableContentWidths[i], ansi.PrintableRuneWidth(cell))
+		}
+
+		// the hotkeys are displayed after the rows
+		m.tableHotkeyWidth = max(m.tableHotkeyWidth, hotkeyWidth(choice))
+	}
+
+	m.layoutTable()
+
+	return changed
+}
+
+// layoutTable determines the widths of the columns such that the rows fit the
+// terminal width. It has to be called whenever the terminal width changes.
+func (m *Model[T]) layoutTable() {
+	if !m.isTable() {
+		return
+	}
+
+	widths := append([]int(nil), m.tableContentWidths...)
+
+	if m.width > 0 {
+		fitColumnWidths(widths, m.width-m.tableRowPrefixWidth()-m.tableHotkeyWidth)
+	}
+
+	headers := make([]string, 0, len(m.Columns))
+	for _, column := range m.Columns {
+		headers = append(headers, column.Header)
+	}
+
+	m.tableColumnWidths = widths
+	m.tableHeader = formatTableRow(headers, widths)
+}
+
+// tableRow formats the cells of the choice as aligned row and maps the
+// positions of the runes that matched the filter from the string
+// representation of the choice to the row. Matches in the truncated parts of
+// the cells are omitted.
+func (m *Model[T]) tableRow(choice *Choice[T]) (string, []int) {
+	row := formatTableRow(choice.tableCells, m.tableColumnWidths)
+	if len(choice.matchPositions) == 0 {
+		return row, nil
+	}
+
+	positions := make([]int, 0, len(choice.matchPositions))
+	strOffset, rowOffset := 0, 0
+
+	for i, cell := range choice.tableCells {
+		cellLength := utf8.RuneCountInString(cell)
+
+		visible := cellLength
+		if ansi.PrintableRuneWidth(cell) > m.tableColumnWidths[i] {
+			visible = m.tableColumnWidths[i] - ansi.PrintableRuneWidth(tableEllipsis)
+		}
+
+		for _, pos := range choice.matchPositions {
+			if pos >= strOffset && pos < strOffset+visible {
+				positions = append(positions, rowOffset+pos-strOffset)
+			}
+		}
+
+		// the cells are separated by a single space in the string
+		// representation
+		strOffset += cellLength + 1
+		rowOffset += m.tableColumnWidths[i] + tableColumnGap
+	}
+
+	return row, positions
+}
+
+// displayedChoices returns the displayed choices as they are rendered. In
+// table mode, these are copies of the choices with the aligned rows as string
+// representations, such that rendering the view does not modify the choices.
+func (m *Model[T]) displayedChoices() []*Choice[T] {
+	if !m.isTable() {
+		return m.currentChoices
+	}
+
+	displayed := make([]*Choice[T], 0, len(m.currentChoices))
+
+	for _, choice := range m.currentChoices {
+		if choice.create {
+			displayed = append(displayed, choice)
+
+			continue
+		}
+
+		row := *choice
+		row.String, row.matchPositions = m.tableRow(choice)
+		displayed = append(displayed, &row)
+	}
+
+	return displayed
+}
+
+func (m *Model[T]) tableCells(value T) []string {
+	cells := make([]string, 0, len(m.Columns))
+	for _, column := range m.Columns {
+		cells = append(cells, column.Value(value))
+	}
+
+	return cells
+}
+
+// tableRowPrefixWidth returns the width in front of each row in the default
+// templates.
+func (m *Model[T]) tableRowPrefixWidth() int {
+	if m.QuickSelect {
+		return m.rowPrefixWidth + quickSelectLabelWidth
+	}
+
+	return m.rowPrefixWidth
+}
+
+// fitColumnWidths shrinks the widest columns until the table fits the given
+// width or all columns are shrunk to the minimum width.
+func fitColumnWidths(widths []int, width int) {
+	total := (len(widths) - 1) * tableColumnGap
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > width {
+		widest := 0
+
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+
+		if widths[widest] <= tableMinColumnWidth {
+			return
+		}
+
+		widths[widest]--
+		total--
+	}
+}
+
+// formatTableRow pads or truncates each cell to the width of its column.
+// Trailing spaces of the last column are omitted.
+func formatTableRow(cells []string, widths []int) string {
+	var row strings.Builder
+
+	for i, cell := range cells {
+		if i > 0 {
+			row.WriteString(strings.Repeat(" ", tableColumnGap))
+		}
+
+		cellWidth := ansi.PrintableRuneWidth(cell)
+		if cellWidth > widths[i] {
+			cell = truncate.StringWithTail(cell, uint(widths[i]), tableEllipsis)
+			cellWidth = ansi.PrintableRuneWidth(cell)
+		}
+
+		row.WriteString(cell)
+
+		if i < len(cells)-1 {
+			row.WriteString(strings.Repeat(" ", max(0, widths[i]-cellWidth)))
+		}
+	}
+
+	return row.String()
+}
@@ -0,0 +1,146 @@
+package selection
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// DefaultTreeTemplate defines the default appearance of the tree selection
+	// and can be copied as a starting point for a custom template.
+	DefaultTreeTemplate = `
+{{- template "header" . }}
+
+{{- range  $i, $choice := .Choices }}
+  {{- template "scrollHint" $i }}
+  {{- if $.IsQuickSelect }}{{ template "quickSelectLabel" $i }}{{ end }}
+
+  {{- if eq $.SelectedIndex $i }}
+    {{- print (Foreground "32" (Bold "▸ ")) -}}
+  {{- else }}
+    {{- print "  " -}}
+  {{- end }}
+
+  {{- Repeat "  " (Depth $choice) -}}
+
+  {{- if not (HasChildren $choice) }}
+    {{- print "  " -}}
+  {{- else if IsExpanded $choice }}
+    {{- print "- " -}}
+  {{- else }}
+    {{- print "+ " -}}
+  {{- end }}
+
+  {{- if $choice.Disabled }}
+    {{- print (Disabled $choice) }}
+  {{- else if eq $.SelectedIndex $i }}
+    {{- print (Selected $choice) }}
+  {{- else }}
+    {{- print (Unselected $choice) }}
+  {{- end }}
+  {{- template "hotkey" $choice }}
+  {{- "\n" }}
+{{- end}}`
+
+	// DefaultTreeResultTemplate defines the default appearance with which the
+	// final result of the tree selection is presented.
+	DefaultTreeResultTemplate = `
+	{{- print .Prompt " " -}}
+	{{- range $i, $choice := .FinalPath }}
+		{{- if $i }}{{ " / " }}{{ end }}{{ Final $choice }}
+	{{- end }}
+	{{- "\n" -}}
+	`
+)
+
+// TreeNode is a node of a tree selection. It embeds the Choice that represents
+// the node such that it can be configured like any other choice.
+type TreeNode[T any] struct {
+	*Choice[T]
+
+	// Children holds the child nodes that are displayed below the node when
+	// it is expanded.
+	Children []*TreeNode[T]
+
+	// Expanded determines whether the children of the node are displayed.
+	Expanded bool
+
+	parent *TreeNode[T]
+	depth  int
+}
+
+// NewTreeNode creates a new tree node for the given value with the provided
+// child nodes.
+func NewTreeNode[T any](value T, children ...*TreeNode[T]) *TreeNode[T] {
+	return &TreeNode[T]{Choice: newChoice(value), Children: children}
+}
+
+// Parent returns the parent node or nil for root nodes.
+func (n *TreeNode[T]) Parent() *TreeNode[T] {
+	return n.parent
+}
+
+// Path returns the values of all nodes from the root node to this node.
+func (n *TreeNode[T]) Path() []T {
+	var path []T
+
+	for node := n; node != nil; node = node.parent {
+		path = append([]T{node.Value}, path...)
+	}
+
+	return path
+}
+
+// TreeSelection represents a configurable selection prompt for hierarchical
+// choices. Nodes with children can be expanded and collapsed and filtering
+// keeps the ancestors of matching nodes visible. It builds upon Selection such
+// that filtering, pagination and customization work the same way.
+type TreeSelection[T any] struct {
+	*Selection[T]
+
+	tree *treeSource[T]
+}
+
+// NewTree creates a new tree selection prompt with the given root nodes. It can
+// be configured in the same way as a regular selection, however, Template and
+// ResultTemplate additionally have access to the following functions and
+// variables:
+//
+//   - Depth(*Choice) int: The nesting depth of a choice, starting with 0 for
+//     root nodes.
+//   - HasChildren(*Choice) bool: Whether or not a choice has children.
+//   - IsExpanded(*Choice) bool: Whether or not the children of a choice are
+//     displayed. While filtering, all ancestors of matching choices are
+//     expanded.
+//
+// In addition to FinalChoice, the ResultTemplate has access to FinalPath which
+// holds the choices from the root node to the chosen node. The template
+// variable AllChoices is not available for tree selections. See the Selection
+// properties for more documentation.
+func NewTree[T any](prompt string, roots []*TreeNode[T]) *TreeSelection[T] {
+	tree := newTreeSource(roots)
+
+	selection := NewFromSource[T](prompt, tree)
+	selection.Template = DefaultTreeTemplate
+	selection.ResultTemplate = DefaultTreeResultTemplate
+
+	return &TreeSelection[T]{Selection: selection, tree: tree}
+}
+
+// RunPrompt executes the tree selection prompt and returns the values of the
+// path from the root node to the chosen node.
+func (s *TreeSelection[T]) RunPrompt() ([]T, error) {
+	m := NewTreeModel(s)
+
+	p := tea.NewProgram(m, s.programOptions()...)
+
+	_, err := p.Run()
+	if err != nil {
+		return nil, fmt.Errorf("running prompt: %w", err)
+	}
+
+	s.printResultView(m)
+
+	return m.ValuePath()
+}
@@ -0,0 +1,83 @@
+package selection_test
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func configTree() []*selection.TreeNode[string] {
+	return []*selection.TreeNode[string]{
+		selection.NewTreeNode("config",
+			selection.NewTreeNode[string]("app.yaml"),
+			selection.NewTreeNode("db",
+				selection.NewTreeNode[string]("primary.yaml"),
+				selection.NewTreeNode[string]("replica.yaml"),
+			),
+		),
+		selection.NewTreeNode("logs",
+			selection.NewTreeNode[string]("app.log"),
+		),
+	}
+}
+
+func TestTree(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewTreeModel(selection.NewTree("foo:", configTree()))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, tea.KeyRight, tea.KeyDown, tea.KeyDown, tea.KeyRight, tea.KeyRight)
+	assertNoError(t, m.Model)
+	test.AssertGoldenView(t, m, "tree_expanded.golden")
+
+	assertPath(t, m, []string{"config", "db", "primary.yaml"})
+
+	test.Update(t, m, tea.KeyLeft)
+	assertPath(t, m, []string{"config", "db"})
+
+	test.Update(t, m, tea.KeyLeft)
+	test.AssertGoldenView(t, m, "tree_collapsed.golden")
+	assertPath(t, m, []string{"config", "db"})
+
+	test.Update(t, m, tea.KeyDown)
+	assertPath(t, m, []string{"logs"})
+
+	test.Update(t, m, tea.KeyEnter)
+	test.AssertGoldenView(t, m, "tree_result.golden")
+}
+
+func TestTreeFiltered(t *testing.T) {
+	t.Parallel()
+
+	m := selection.NewTreeModel(selection.NewTree("foo:", configTree()))
+	m.ColorProfile = termenv.TrueColor
+
+	test.Run(t, m, append(test.MsgsFromText("replica"), tea.KeyDown, tea.KeyDown)...)
+	assertNoError(t, m.Model)
+	test.AssertGoldenView(t, m, "tree_filtered.golden")
+
+	assertPath(t, m, []string{"config", "db", "replica.yaml"})
+
+	// collapsed nodes stay collapsed when the filter is cleared such that the
+	// cursor is moved to the first node as the highlighted one disappears
+	test.Update(t, m, tea.KeyEsc)
+	assertPath(t, m, []string{"config"})
+}
+
+func assertPath[T any](tb testing.TB, m *selection.TreeModel[T], expected []T) {
+	tb.Helper()
+
+	path, err := m.ValuePath()
+	if err != nil {
+		tb.Fatalf("value path: %v", err)
+	}
+
+	if !reflect.DeepEqual(path, expected) {
+		tb.Errorf("unexpected path: %v, expected %v", path, expected)
+	}
+}
@@ -0,0 +1,197 @@
+package selection
+
+import (
+	"fmt"
+	"text/template"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TreeModel implements the bubbletea.Model for a tree selection prompt.
+type TreeModel[T any] struct {
+	*Model[T]
+
+	tree *treeSource[T]
+}
+
+// ensure that the Model interface is implemented.
+var _ tea.Model = &TreeModel[any]{}
+
+// NewTreeModel returns a new tree selection prompt model for the provided
+// nodes.
+func NewTreeModel[T any](treeSelection *TreeSelection[T]) *TreeModel[T] {
+	m := &TreeModel[T]{
+		Model: NewModel(treeSelection.Selection),
+		tree:  treeSelection.tree,
+	}
+
+	m.Model.canCreateChoices = false
+	m.Model.validateKeyMap = func() error {
+		return validateTreeKeyMap(m.Selection, m.hotkeys()...)
+	}
+	m.Model.finalTemplateData = func() (map[string]interface{}, error) {
+		node, err := m.ValueAsNode()
+		if err != nil {
+			return nil, err
+		}
+
+		var path []*Choice[T]
+
+		for n := node; n != nil; n = n.parent {
+			path = append([]*Choice[T]{n.Choice}, path...)
+		}
+
+		return map[string]interface{}{"FinalChoice": node.Choice, "FinalPath": path}, nil
+	}
+
+	m.Model.extraTemplateFuncs = template.FuncMap{
+		"Depth": func(c *Choice[T]) int {
+			node := m.tree.node(c)
+			if node == nil {
+				return 0
+			}
+
+			return node.depth
+		},
+		"HasChildren": func(c *Choice[T]) bool {
+			node := m.tree.node(c)
+
+			return node != nil && len(node.Children) > 0
+		},
+		"IsExpanded": m.isExpanded,
+	}
+
+	return m
+}
+
+// Init initializes the tree selection prompt model.
+func (m *TreeModel[T]) Init() tea.Cmd {
+	m.tree.reindex()
+	m.tree.match = m.matchesFilter
+
+	return m.Model.Init()
+}
+
+// ValueAsNode returns the currently highlighted node or the final node after
+// the prompt has concluded.
+func (m *TreeModel[T]) ValueAsNode() (*TreeNode[T], error) {
+	choice, err := m.ValueAsChoice()
+	if err != nil {
+		return nil, err
+	}
+
+	node := m.tree.node(choice)
+	if node == nil {
+		return nil, fmt.Errorf("choice %q is not part of the tree", choice.String)
+	}
+
+	return node, nil
+}
+
+// ValuePath returns the values of the path from the root node to the currently
+// highlighted node or to the final node after the prompt has concluded.
+func (m *TreeModel[T]) ValuePath() ([]T, error) {
+	node, err := m.ValueAsNode()
+	if err != nil {
+		return nil, err
+	}
+
+	return node.Path(), nil
+}
+
+// Update updates the model based on the received message. Nodes can only be
+// expanded and collapsed while no filter text is entered, otherwise the keys
+// are passed on to the filter input.
+func (m *TreeModel[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.Err != nil {
+		return m, tea.Quit
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || m.quitting || m.filterText() != "" {
+		_, cmd := m.Model.Update(msg)
+
+		return m, cmd
+	}
+
+	m.userInteracted()
+
+	switch {
+	case keyMatches(keyMsg, m.KeyMap.Expand):
+		m.expand()
+	case keyMatches(keyMsg, m.KeyMap.Collapse):
+		m.collapse()
+	default:
+		_, cmd := m.Model.Update(msg)
+
+		return m, cmd
+	}
+
+	return m, m.updatePreview()
+}
+
+// expand displays the children of the highlighted node or moves the cursor to
+// its first child if it is already expanded.
+func (m *TreeModel[T]) expand() {
+	node, err := m.ValueAsNode()
+	if err != nil || len(node.Children) == 0 {
+		return
+	}
+
+	if node.Expanded {
+		m.cursorDown()
+
+		return
+	}
+
+	node.Expanded = true
+	m.refresh()
+}
+
+// collapse hides the children of the highlighted node or moves the cursor to
+// its parent if it is already collapsed.
+func (m *TreeModel[T]) collapse() {
+	node, err := m.ValueAsNode()
+	if err != nil {
+		return
+	}
+
+	if node.Expanded && len(node.Children) > 0 {
+		node.Expanded = false
+		m.refresh()
+
+		return
+	}
+
+	if node.parent == nil {
+		return
+	}
+
+	position, ok := m.tree.expandedPosition(node.parent)
+	if ok {
+		m.moveToPosition(position)
+		m.ensureSelectable()
+	}
+}
+
+// refresh updates the displayed choices after nodes were expanded or collapsed
+// while keeping the cursor on the highlighted node.
+func (m *TreeModel[T]) refresh() {
+	m.tree.invalidateCache()
+
+	if m.height > 0 {
+		m.forceUpdatePageSizeForHeight()
+	} else {
+		m.moveToPosition(m.currentPosition())
+		m.ensureSelectable()
+	}
+}
+
+func (m *TreeModel[T]) isExpanded(choice *Choice[T]) bool {
+	node := m.tree.node(choice)
+	if node == nil || len(node.Children) == 0 {
+		return false
+	}
+
+	return node.Expanded || m.filterText() != ""
+}
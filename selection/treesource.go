@@ -0,0 +1,203 @@
+package selection
+
+// treeSource is the ChoiceSource of a tree selection. It provides the nodes
+// that are currently visible in depth-first order. The index of each choice is
+// the position of the node within all nodes in depth-first order such that it
+// does not change when nodes are expanded or collapsed.
+type treeSource[T any] struct {
+	roots []*TreeNode[T]
+	nodes []*TreeNode[T]
+
+	// match decides whether a choice matches the filter text, it is set by
+	// the model according to its filter configuration.
+	match func(filterText string, choice *Choice[T]) bool
+
+	// expanded caches the indices of the nodes that are visible according to
+	// the expansion state of their ancestors.
+	expanded       []int
+	expandedCached bool
+
+	// filtered caches the indices of the nodes that match filterText along
+	// with their ancestors.
+	filtered       []int
+	filterText     string
+	filteredCached bool
+}
+
+var _ ChoiceSource[any] = &treeSource[any]{}
+
+func newTreeSource[T any](roots []*TreeNode[T]) *treeSource[T] {
+	return &treeSource[T]{roots: roots}
+}
+
+func (s *treeSource[T]) Len() int {
+	return len(s.expandedIndices())
+}
+
+func (s *treeSource[T]) Range(offset int, limit int) []*Choice[T] {
+	return s.choices(s.expandedIndices(), offset, limit)
+}
+
+func (s *treeSource[T]) Query(
+	filterText string, offset int, limit int,
+) (choices []*Choice[T], available int) {
+	if !s.filteredCached || s.filterText != filterText {
+		s.filterText = filterText
+		s.filtered = s.filtered[:0]
+
+		for _, root := range s.roots {
+			s.filtered, _ = s.filter(root, s.filtered)
+		}
+
+		s.filteredCached = true
+	}
+
+	return s.choices(s.filtered, offset, limit), len(s.filtered)
+}
+
+func (s *treeSource[T]) choices(indices []int, offset int, limit int) []*Choice[T] {
+	offset = max(0, min(offset, len(indices)))
+	page := indices[offset:min(len(indices), offset+max(0, limit))]
+
+	choices := make([]*Choice[T], 0, len(page))
+	for _, idx := range page {
+		choices = append(choices, s.nodes[idx].Choice)
+	}
+
+	return choices
+}
+
+// reindex assigns the indices, parents and depths of all nodes.
+func (s *treeSource[T]) reindex() {
+	s.nodes = s.nodes[:0]
+
+	var walk func(node *TreeNode[T], parent *TreeNode[T], depth int)
+
+	walk = func(node *TreeNode[T], parent *TreeNode[T], depth int) {
+		node.idx = len(s.nodes)
+		node.parent = parent
+		node.depth = depth
+		s.nodes = append(s.nodes, node)
+
+		for _, child := range node.Children {
+			walk(child, node, depth+1)
+		}
+	}
+
+	for _, root := range s.roots {
+		walk(root, nil, 0)
+	}
+
+	s.invalidateCache()
+}
+
+func (s *treeSource[T]) expandedIndices() []int {
+	if s.expandedCached {
+		return s.expanded
+	}
+
+	s.expanded = s.expanded[:0]
+
+	var walk func(node *TreeNode[T])
+
+	walk = func(node *TreeNode[T]) {
+		s.expanded = append(s.expanded, node.idx)
+
+		if !node.Expanded {
+			return
+		}
+
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+
+	for _, root := range s.roots {
+		walk(root)
+	}
+
+	s.expandedCached = true
+
+	return s.expanded
+}
+
+// filter appends the index of the node and the indices of its descendants to
+// the indices slice if they or any of their descendants match the cached filter
+// text.
+func (s *treeSource[T]) filter(node *TreeNode[T], indices []int) ([]int, bool) {
+	start := len(indices)
+	indices = append(indices, node.idx)
+
+	matched := s.match == nil || s.match(s.filterText, node.Choice)
+
+	for _, child := range node.Children {
+		var childMatched bool
+
+		indices, childMatched = s.filter(child, indices)
+		matched = matched || childMatched
+	}
+
+	if !matched {
+		return indices[:start], false
+	}
+
+	return indices, true
+}
+
+// invalidateCache has to be called when nodes are expanded or collapsed or
+// when the filter behavior changes without a change of the filter text.
+func (s *treeSource[T]) invalidateCache() {
+	s.expandedCached = false
+	s.filteredCached = false
+}
+
+// invalidateFilterCache has to be called when the filter behavior changes
+// without a change of the filter text.
+func (s *treeSource[T]) invalidateFilterCache() {
+	s.filteredCached = false
+}
+
+// node returns the node that is represented by the given choice.
+func (s *treeSource[T]) node(choice *Choice[T]) *TreeNode[T] {
+	if choice == nil || choice.idx < 0 || choice.idx >= len(s.nodes) {
+		return nil
+	}
+
+	return s.nodes[choice.idx]
+}
+
+// position returns the position of the node that is represented by the given
+// choice among the nodes that are visible for the filter text.
+func (s *treeSource[T]) position(filterText string, choice *Choice[T]) (int, bool) {
+	node := s.node(choice)
+	if node == nil || node.Choice != choice {
+		return 0, false
+	}
+
+	if filterText == "" {
+		return s.expandedPosition(node)
+	}
+
+	// make sure that the filtered nodes are cached
+	s.Query(filterText, 0, 0)
+
+	for position, idx := range s.filtered {
+		if idx == node.idx {
+			return position, true
+		}
+	}
+
+	return 0, false
+}
+
+// expandedPosition returns the position of the node among the visible nodes
+// if no filter is applied.
+func (s *treeSource[T]) expandedPosition(node *TreeNode[T]) (int, bool) {
+	for position, idx := range s.expandedIndices() {
+		if idx == node.idx {
+			return position, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,85 @@
+package selection
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeAheadTimeout is the pause after which the keystrokes for the type-ahead
+// search are discarded.
+const typeAheadTimeout = time.Second
+
+// typeAheadResetMsg discards the type-ahead keystrokes unless another key was
+// pressed after the message was scheduled.
+type typeAheadResetMsg struct {
+	seq int
+}
+
+// typeAhead moves the cursor to the next choice that starts with the
+// keystrokes that were typed in quick succession. It is used instead of the
+// filter input when filtering is disabled.
+func (m *Model[T]) typeAhead(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyRunes:
+		m.typeAheadText += string(msg.Runes)
+	case tea.KeySpace:
+		m.typeAheadText += " "
+	default:
+		return nil
+	}
+
+	position, ok := m.typeAheadPosition()
+	if ok {
+		m.moveToPosition(position)
+	}
+
+	m.typeAheadSeq++
+	seq := m.typeAheadSeq
+
+	return tea.Tick(typeAheadTimeout, func(time.Time) tea.Msg {
+		return typeAheadResetMsg{seq: seq}
+	})
+}
+
+// typeAheadPosition returns the position of the choice to which the cursor
+// jumps for the current type-ahead text. The first keystroke moves the cursor
+// to the next matching choice while further keystrokes refine the search
+// starting with the highlighted choice. Repeatedly typing the same rune cycles
+// through the choices that start with it.
+func (m *Model[T]) typeAheadPosition() (int, bool) {
+	text := strings.ToLower(m.typeAheadText)
+	runes := []rune(text)
+
+	if len(runes) == 1 {
+		return m.searchPrefix(text, m.currentPosition()+1)
+	}
+
+	position, ok := m.searchPrefix(text, m.currentPosition())
+	if ok || strings.Trim(text, string(runes[0])) != "" {
+		return position, ok
+	}
+
+	return m.searchPrefix(string(runes[0]), m.currentPosition()+1)
+}
+
+// searchPrefix returns the position of the first choice that is not disabled
+// and starts with the lower case prefix, starting at the given position and
+// wrapping around.
+func (m *Model[T]) searchPrefix(prefix string, start int) (int, bool) {
+	for i := 0; i < m.availableChoices; i++ {
+		position := (start + i) % m.availableChoices
+
+		choice := m.choiceAtPosition(position)
+		if choice == nil || choice.Disabled {
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(choice.String), prefix) {
+			return position, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,55 @@
+package selection_test
+
+import (
+	"testing"
+
+	"github.com/erikgeiser/promptkit/selection"
+	"github.com/erikgeiser/promptkit/test"
+	"github.com/muesli/termenv"
+)
+
+func TestTypeAhead(t *testing.T) {
+	t.Parallel()
+
+	s := selection.New("foo:", []string{"Apple", "apricot", "Banana", "blueberry", "cherry"})
+	s.Filter = nil
+	s.PageSize = 2
+	s.ColorProfile = termenv.TrueColor
+
+	m := selection.NewModel(s)
+
+	test.Run(t, m)
+	staleReset := test.Update(t, m, test.KeyMsg('b'))
+	test.Update(t, m, test.KeyMsg('l'))
+	assertNoError(t, m)
+	test.AssertGoldenView(t, m, "type_ahead.golden")
+
+	if choice := getChoice(t, m); choice != "blueberry" {
+		t.Errorf("unexpected choice: %q, expected blueberry", choice)
+	}
+
+	// a reset that was scheduled before the last keystroke is ignored
+	test.Update(t, m, staleReset())
+	reset := test.Update(t, m, test.KeyMsg('u'))
+
+	if choice := getChoice(t, m); choice != "blueberry" {
+		t.Errorf("unexpected choice: %q, expected blueberry", choice)
+	}
+
+	test.Update(t, m, reset())
+
+	for _, expected := range []string{"Apple", "apricot", "Apple"} {
+		test.Update(t, m, test.KeyMsg('a'))
+
+		if choice := getChoice(t, m); choice != expected {
+			t.Errorf("unexpected choice: %q, expected %s", choice, expected)
+		}
+	}
+
+	// the cursor stays if no choice matches
+	test.Update(t, m, test.KeyMsg('x'))
+
+	if choice := getChoice(t, m); choice != "Apple" {
+		t.Errorf("unexpected choice: %q, expected Apple", choice)
+	}
+}